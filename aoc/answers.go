@@ -0,0 +1,81 @@
+// Expected answers for each day, kept in a file answers.json in the day's
+// directory, so that all the solutions can be checked automatically (see
+// the tests for the aoc command). Each entry gives the expected answer (or
+// error) for one part of the puzzle on one input file, e.g.,
+//
+//	[
+//		{"input":"sample.txt","part":1,"answer":"24000"},
+//		{"input":"input.txt","part":2,"answer":"143208","note":"s/b 144019"}
+//	]
+//
+// AK, Dec 2022
+
+package aoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// Name of the file with the expected answers, in each day's directory
+const AnswersFile = "answers.json"
+
+// The expected answer for one part on one input file
+type Answer struct {
+	Input  string `json:"input"`            // input file, in the day's directory
+	Part   int    `json:"part"`             // 1 or 2
+	Answer string `json:"answer,omitempty"` // answer, as returned by the solver
+	Error  string `json:"error,omitempty"`  // or the error message, if it fails
+	Slow   bool   `json:"slow,omitempty"`   // takes a long time, only check on request
+	Note   string `json:"note,omitempty"`   // e.g., why the answer is not the accepted one
+}
+
+// Read the expected answers from a file, checking that each one is valid
+func ReadAnswers(fname string) ([]Answer, error) {
+	data, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	var answers []Answer
+	if err := json.Unmarshal(data, &answers); err != nil {
+		return nil, fmt.Errorf("%s: %w", fname, err)
+	}
+	for i, a := range answers {
+		if a.Input == "" || filepath.Base(a.Input) != a.Input {
+			return nil, fmt.Errorf("%s: entry %d: invalid input file %q", fname, i+1, a.Input)
+		}
+		if a.Part != 1 && a.Part != 2 {
+			return nil, fmt.Errorf("%s: entry %d: invalid part %d", fname, i+1, a.Part)
+		}
+		if (a.Answer == "") == (a.Error == "") {
+			return nil, fmt.Errorf("%s: entry %d: need either an answer or an error", fname, i+1)
+		}
+	}
+	return answers, nil
+}
+
+// Write the expected answers to a file, one entry per line so that changes
+// are easy to see in diffs
+func WriteAnswers(fname string, answers []Answer) error {
+	var b, line bytes.Buffer
+	enc := json.NewEncoder(&line)
+	enc.SetEscapeHTML(false) // keep answers readable
+	b.WriteString("[\n")
+	for i, a := range answers {
+		line.Reset()
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+		b.WriteString("\t")
+		b.Write(bytes.TrimSpace(line.Bytes()))
+		if i < len(answers)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+	return ioutil.WriteFile(fname, b.Bytes(), 0644)
+}
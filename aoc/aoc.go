@@ -0,0 +1,102 @@
+// Registry of the Advent of Code 2022 solutions, so that one program can
+// run any day. Each day's package registers itself in an init() function,
+// and the aoc command imports all the day packages.
+//
+// AK, Dec 2022
+
+package aoc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// Interface implemented by each day's solution: parse the input once, then
+// solve either part, returning the answer as a string. The parts must not
+// change the parsed input, so that they can be run in any order.
+type Solver interface {
+	Parse(r io.Reader) error
+	Part1() (string, error)
+	Part2() (string, error)
+}
+
+// Optional interface for a day that can show its input (or how it solves
+// it) in other forms, e.g., as a Graphviz graph, for the "aoc show" command.
+// Show is called after Parse, with one of the formats it gives.
+type Shower interface {
+	Formats() []string
+	Show(w io.Writer, format string) error
+}
+
+// Optional interface for a day with options that change how it solves the
+// puzzle (e.g., which kind of numbers to use), set with "aoc run --opt
+// name=value". SetOption is called before Parse, and returns an error for
+// an unknown option or value.
+type Optioner interface {
+	SetOption(name, value string) error
+}
+
+// Optional interface for a day whose puzzle uses different settings for
+// the sample than for the real input (e.g., which row to look at), which
+// would otherwise have to be guessed from the input. UseSample is called
+// before SetOption (so options can still change the settings) and Parse,
+// when running on the sample.
+type Sampler interface {
+	UseSample()
+}
+
+// Returned by a part that has no solution in Go
+var ErrNoSolution = errors.New("no Go solution for this part")
+
+// All the days registered so far, by day number, with a function to create
+// a new solver for each
+var days = map[int]func() Solver{}
+
+// Register a day's solution, called from the init() function of each day
+func Register(day int, newSolver func() Solver) {
+	if _, ok := days[day]; ok {
+		panic(fmt.Sprintf("Day %d registered twice", day))
+	}
+	days[day] = newSolver
+}
+
+// Create a new solver for a day, false if there is none
+func New(day int) (Solver, bool) {
+	f, ok := days[day]
+	if !ok {
+		return nil, false
+	}
+	return f(), true
+}
+
+// Sorted list of the days that have been registered
+func Days() []int {
+	dd := []int{}
+	for d := range days {
+		dd = append(dd, d)
+	}
+	sort.Ints(dd)
+	return dd
+}
+
+// Name of the directory for a day, e.g., "day07"
+func DayDir(day int) string {
+	return fmt.Sprintf("day%02d", day)
+}
+
+// Read all the lines from an input, removing any blank lines at the end
+func Lines(r io.Reader) ([]string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	for len(lines) > 0 && len(strings.TrimSpace(lines[len(lines)-1])) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
@@ -0,0 +1,183 @@
+// Utility functions for Advent of Code, shared by all the days (these used
+// to be copied into each day as utils.go). Generic where possible, so they
+// work for any kind of number.
+//
+// AK, Dec 2022
+
+package aocutil
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+// Any kind of signed integer
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Any kind of unsigned integer (including byte)
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Any kind of number
+type Number interface {
+	Signed | Unsigned | ~float32 | ~float64
+}
+
+// Anything that can be compared with < and >
+type Ordered interface {
+	Number | ~string
+}
+
+// Read lines from the input file, remove any blank lines at end
+func ReadLines(filename string) []string {
+
+	// Read data
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	// Split into lines
+	lines := strings.Split(string(data), "\n")
+
+	// Remove any blank lines
+	for len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// Parse an integer that is known to be valid (e.g., already checked when
+// the input was read), panic if not. Use ParseInt to get an error instead.
+func Atoi(s string) int {
+	n, err := ParseInt(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Parse a 64-bit integer that is known to be valid, panic if not
+func Atoi64(s string) int64 {
+	n, err := ParseInt64(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Parse a float that is known to be valid, panic if not
+func Atof(s string) float64 {
+	n, err := ParseFloat(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Maximum of a list (of numbers or strings), zero value if empty
+func Max[T Ordered](l []T) T {
+	var y T
+	for i := 0; i < len(l); i++ {
+		if i == 0 || l[i] > y {
+			y = l[i]
+		}
+	}
+	return y
+}
+
+// Minimum of a list (of numbers or strings), zero value if empty
+func Min[T Ordered](l []T) T {
+	var y T
+	for i := 0; i < len(l); i++ {
+		if i == 0 || l[i] < y {
+			y = l[i]
+		}
+	}
+	return y
+}
+
+// Sum of a list
+func Sum[T Number](l []T) T {
+	var y T
+	for i := 0; i < len(l); i++ {
+		y += l[i]
+	}
+	return y
+}
+
+// Intersection of two lists, i.e., elements of a that are also in b
+func Intersection[T comparable](a, b []T) []T {
+	res := []T{}
+	for i := 0; i < len(a); i++ {
+		if In(a[i], b) {
+			res = append(res, a[i])
+		}
+	}
+	return res
+}
+
+// Union of two lists, i.e., all of a, followed by elements of b that are
+// not already there (warning: this will include duplicates in list a)
+func Union[T comparable](a, b []T) []T {
+	res := append([]T{}, a...)
+	for i := 0; i < len(b); i++ {
+		if !In(b[i], res) {
+			res = append(res, b[i])
+		}
+	}
+	return res
+}
+
+// Is element in a list?
+func In[T comparable](c T, s []T) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Shallow compare two lists element-by-element, and report
+// if they are the same
+func Same[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Flexible version of math.Abs
+func Abs[T Signed | ~float32 | ~float64](x T) T {
+	if x < 0 {
+		return -x
+	} else {
+		return x
+	}
+}
+
+// Simple inline if-then-else
+func IfElse[T any](cond bool, a, b T) T {
+	if cond {
+		return a
+	} else {
+		return b
+	}
+}
+
+// Panic if a test condition is not true
+func Assert(cond bool, msg string) {
+	if !cond {
+		panic(msg)
+	}
+}
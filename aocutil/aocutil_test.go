@@ -0,0 +1,54 @@
+// Unit tests for the shared utility functions
+
+package aocutil
+
+import (
+	"testing"
+)
+
+func TestMinMax(t *testing.T) {
+	if m := Max([]int{3, 9, -2}); m != 9 {
+		t.Errorf("Max of ints is %d instead of 9", m)
+	}
+	if m := Min([]int64{3, 9, -2}); m != -2 {
+		t.Errorf("Min of int64s is %d instead of -2", m)
+	}
+	if m := Max([]string{"b", "c", "a"}); m != "c" {
+		t.Errorf("Max of strings is %q instead of c", m)
+	}
+	if m := Min([]float64{}); m != 0 {
+		t.Errorf("Min of empty list is %v instead of 0", m)
+	}
+}
+
+func TestLists(t *testing.T) {
+	a := []byte("abcd")
+	b := []byte("cdef")
+	if r := Intersection(a, b); string(r) != "cd" {
+		t.Errorf("Intersection is %q instead of cd", r)
+	}
+	if r := Union(a, b); string(r) != "abcdef" {
+		t.Errorf("Union is %q instead of abcdef", r)
+	}
+	if !In('e', b) || In('a', b) {
+		t.Errorf("In gives wrong result")
+	}
+	if !Same(a, []byte("abcd")) || Same(a, b) || Same(a, a[:2]) {
+		t.Errorf("Same gives wrong result")
+	}
+	if s := Sum([]int64{1, 2, 3}); s != 6 {
+		t.Errorf("Sum is %d instead of 6", s)
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	if Abs(-3) != 3 || Abs(int64(-3)) != 3 || Abs(2.5) != 2.5 {
+		t.Errorf("Abs gives wrong result")
+	}
+	if IfElse(true, 'A', 'B') != 'A' || IfElse(false, "x", "y") != "y" {
+		t.Errorf("IfElse gives wrong result")
+	}
+	if Atoi("-42") != -42 || Atoi64("12977110973564") != 12977110973564 {
+		t.Errorf("Atoi gives wrong result")
+	}
+}
@@ -0,0 +1,150 @@
+// Parsing numbers from the input, returning errors that say where the
+// problem is (file, line, column, and the offending token), rather than
+// printing a message and carrying on with -1, which is a valid value in
+// many puzzles.
+//
+// AK, Dec 2022
+
+package aocutil
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error in the input, with as much as is known about where it is
+type ParseError struct {
+	File  string // input file name (filled in by the aoc command)
+	Line  int    // line number, starting at 1 (0 if not known)
+	Col   int    // column of the token, starting at 1 (0 if not known)
+	Token string // the offending token, if any
+	Err   error  // what was wrong
+}
+
+// Show the error as file:line:col: token: message, leaving out any parts
+// that are not known
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	if e.File != "" {
+		b.WriteString(e.File + ":")
+	}
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "%d:", e.Line)
+		if e.Col > 0 {
+			fmt.Fprintf(&b, "%d:", e.Col)
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	if e.Token != "" {
+		fmt.Fprintf(&b, "%q: ", e.Token)
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Kinds of error, which can be checked with errors.Is
+var (
+	ErrInt    = errors.New("invalid integer")
+	ErrFloat  = errors.New("invalid number")
+	ErrFormat = errors.New("invalid format")
+)
+
+// Parse an integer, returning a *ParseError if invalid
+func ParseInt(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, &ParseError{Token: s, Err: ErrInt}
+	}
+	return n, nil
+}
+
+// Parse a 64-bit integer, returning a *ParseError if invalid
+func ParseInt64(s string) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, &ParseError{Token: s, Err: ErrInt}
+	}
+	return n, nil
+}
+
+// Parse a float, returning a *ParseError if invalid
+func ParseFloat(s string) (float64, error) {
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, &ParseError{Token: s, Err: ErrFloat}
+	}
+	return n, nil
+}
+
+// Parse a list of integers separated by sep (e.g., "1,2,3"), ignoring
+// spaces around each one
+func ParseInts(s, sep string) ([]int, error) {
+	nums := []int{}
+	for _, w := range strings.Split(s, sep) {
+		n, err := ParseInt(strings.TrimSpace(w))
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, n)
+	}
+	return nums, nil
+}
+
+// Make an error about a line of the input (line number starting at 1)
+func LineErrorf(line int, format string, args ...any) error {
+	return &ParseError{Line: line, Err: fmt.Errorf(format, args...)}
+}
+
+// Add the line number (starting at 1) to an error, and the column if the
+// error has a token that appears just once in the text of the line
+func AtLine(err error, line int, text string) error {
+	if err == nil {
+		return nil
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		return &ParseError{Line: line, Err: err}
+	}
+	if pe.Line == 0 {
+		pe.Line = line
+		if pe.Col == 0 && pe.Token != "" && strings.Count(text, pe.Token) == 1 {
+			pe.Col = strings.Index(text, pe.Token) + 1
+		}
+	}
+	return err
+}
+
+// Shift the column of an error by an offset, for an error found in part
+// of a line (e.g., matching a pattern to the text after a colon) that starts
+// at that offset in the line
+func AtCol(err error, offset int) error {
+	var pe *ParseError
+	if errors.As(err, &pe) && pe.Col > 0 {
+		pe.Col += offset
+	}
+	return err
+}
+
+// Add the file name to an error, if it is a *ParseError without one,
+// otherwise prefix the error with the file name
+func InFile(err error, fname string) error {
+	if err == nil {
+		return nil
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		return fmt.Errorf("%s: %w", fname, err)
+	}
+	if pe.File == "" {
+		pe.File = fname
+	}
+	return err
+}
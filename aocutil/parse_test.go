@@ -0,0 +1,44 @@
+// Unit tests for the parse helpers
+
+package aocutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+
+	// Valid numbers
+	if n, err := ParseInt("-1"); n != -1 || err != nil {
+		t.Errorf("ParseInt(-1) gives %d, %v", n, err)
+	}
+	if nums, err := ParseInts("1, 2,3", ","); err != nil || !Same(nums, []int{1, 2, 3}) {
+		t.Errorf("ParseInts gives %v, %v", nums, err)
+	}
+
+	// Invalid number, with line and column added
+	_, err := ParseInt64("1q5")
+	if !errors.Is(err, ErrInt) {
+		t.Errorf("ParseInt64 error is %v, not ErrInt", err)
+	}
+	err = InFile(AtLine(err, 3, "x=2, y=1q5"), "input.txt")
+	if s := err.Error(); s != `input.txt:3:8: "1q5": invalid integer` {
+		t.Errorf("Error message is %s", s)
+	}
+
+	// No column if the token appears more than once
+	_, err = ParseInts("7,x,x", ",")
+	if s := AtLine(err, 2, "7,x,x").Error(); s != `2: "x": invalid integer` {
+		t.Errorf("Error message is %s", s)
+	}
+
+	// Errors that are not a ParseError
+	err = InFile(errors.New("oops"), "input.txt")
+	if s := err.Error(); s != "input.txt: oops" {
+		t.Errorf("Error message is %s", s)
+	}
+	if s := LineErrorf(5, "bad %s", "line").Error(); s != "5: bad line" {
+		t.Errorf("Error message is %s", s)
+	}
+}
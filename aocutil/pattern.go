@@ -0,0 +1,288 @@
+// Line patterns, for reading input lines without slicing up words by hand,
+// e.g., for Day 15:
+//
+//	var sensorLine = aocutil.MustPattern("Sensor at x={int}, y={int}: closest beacon is at x={int}, y={int}")
+//	...
+//	var sx, sy, bx, by int
+//	if err := sensorLine.Match(l, &sx, &sy, &bx, &by); err != nil {
+//		return in.AtLine(err, 0)
+//	}
+//
+// Everything outside braces must match exactly, except that a space matches
+// any amount of white space. The fields in braces are:
+//
+//	{int}    an integer, stored in an *int or *int64
+//	{word}   a word (no spaces), stored in a *string
+//	{str}    any text up to whatever follows it in the pattern, in a *string
+//	{ints}   a comma-separated list of integers, in a *[]int
+//	{words}  a comma-separated list of words, in a *[]string
+//	{_}      a word to skip over (e.g., "tunnel" or "tunnels"), not stored
+//
+// The values are stored in the pointers given to Match, in order, or in the
+// fields of a struct, in order, if Match is given a single pointer to one.
+// If a line does not match, the error says where and what was expected.
+//
+// AK, Dec 2022
+
+package aocutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// A compiled line pattern, see MustPattern
+type Pattern struct {
+	text  string    // the pattern as given
+	parts []patPart // literal text and fields, in order
+	nvals int       // number of values stored, i.e., fields other than {_}
+}
+
+// One part of a pattern: literal text, or a field to match
+type patPart struct {
+	lit  string // literal text, if not a field
+	kind string // kind of field, e.g., "int", "" if literal text
+}
+
+// Kinds of fields, and what each is called in error messages
+var patKinds = map[string]string{
+	"int":   "integer",
+	"word":  "word",
+	"str":   "text",
+	"ints":  "list of integers",
+	"words": "list of words",
+	"_":     "word",
+}
+
+// Compile a pattern, or return an error if it is not valid (e.g., has an
+// unknown field, or two fields with nothing between them)
+func NewPattern(text string) (*Pattern, error) {
+	p := &Pattern{text: text}
+	rest := text
+	for len(rest) > 0 {
+		i := strings.IndexByte(rest, '{')
+		if i < 0 {
+			p.parts = append(p.parts, patPart{lit: rest})
+			break
+		}
+		if i > 0 {
+			p.parts = append(p.parts, patPart{lit: rest[:i]})
+		}
+		j := strings.IndexByte(rest, '}')
+		if j < i {
+			return nil, fmt.Errorf("pattern %q: unclosed {", text)
+		}
+		kind := rest[i+1 : j]
+		if _, ok := patKinds[kind]; !ok {
+			return nil, fmt.Errorf("pattern %q: unknown field {%s}", text, kind)
+		}
+		if n := len(p.parts); n > 0 && p.parts[n-1].kind != "" {
+			return nil, fmt.Errorf("pattern %q: {%s} must follow some text", text, kind)
+		}
+		p.parts = append(p.parts, patPart{kind: kind})
+		if kind != "_" {
+			p.nvals++
+		}
+		rest = rest[j+1:]
+	}
+	return p, nil
+}
+
+// Compile a pattern, panic if it is not valid (for patterns that are
+// fixed in the code)
+func MustPattern(text string) *Pattern {
+	p, err := NewPattern(text)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// The pattern as given
+func (p *Pattern) String() string {
+	return p.text
+}
+
+// Match a line against the pattern, storing the values of the fields in
+// dst (pointers, or a single pointer to a struct). Leading and trailing
+// white space in the line is ignored. Returns a *ParseError with the column
+// where the line does not match.
+func (p *Pattern) Match(line string, dst ...any) error {
+
+	// Where to store the values
+	vals, err := p.targets(dst)
+	if err != nil {
+		return err
+	}
+
+	// Match each part in turn, pos is the position in the line
+	pos := len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace))
+	end := len(strings.TrimRightFunc(line, unicode.IsSpace))
+	nval := 0
+	for pi, part := range p.parts {
+
+		// Literal text, a space matches any amount of white space
+		if part.kind == "" {
+			for i := 0; i < len(part.lit); i++ {
+				c := part.lit[i]
+				if c == ' ' {
+					if pos >= end || !isSpace(line[pos]) {
+						return p.mismatch(line, pos, part.lit[i:])
+					}
+					for pos < end && isSpace(line[pos]) {
+						pos++
+					}
+				} else if pos >= end || line[pos] != c {
+					return p.mismatch(line, pos, part.lit[i:])
+				} else {
+					pos++
+				}
+			}
+			continue
+		}
+
+		// A field: find where it ends, then convert it
+		var next string // literal text after the field, if any
+		if pi+1 < len(p.parts) {
+			next = p.parts[pi+1].lit
+		}
+		start := pos
+		switch part.kind {
+		case "int":
+			if pos < end && (line[pos] == '-' || line[pos] == '+') {
+				pos++
+			}
+			for pos < end && line[pos] >= '0' && line[pos] <= '9' {
+				pos++
+			}
+		case "word", "_":
+			for pos < end && !isSpace(line[pos]) && (next == "" || line[pos] != next[0]) {
+				pos++
+			}
+		default: // text or lists, up to the next literal text
+			if next == "" {
+				pos = end
+			} else if i := strings.Index(line[pos:end], next); i >= 0 {
+				pos += i
+			} else {
+				return p.mismatch(line, end, next)
+			}
+		}
+		if pos == start && part.kind != "str" {
+			return p.mismatch(line, pos, "{"+part.kind+"}")
+		}
+		if part.kind == "_" {
+			continue
+		}
+		if err := setValue(vals[nval], part.kind, line[start:pos]); err != nil {
+			var pe *ParseError
+			if errors.As(err, &pe) && part.kind == "int" {
+				pe.Col = start + 1
+			}
+			return err
+		}
+		nval++
+	}
+
+	// Should be nothing left over
+	for pos < end && isSpace(line[pos]) {
+		pos++
+	}
+	if pos < end {
+		return &ParseError{Col: pos + 1, Token: wordAt(line, pos), Err: errors.New("unexpected text at end of line")}
+	}
+	return nil
+}
+
+// Make an error for a line that does not match what is expected next
+// (literal text, or a field) at the given position
+func (p *Pattern) mismatch(line string, pos int, want string) error {
+	if strings.HasPrefix(want, "{") {
+		want = patKinds[want[1:len(want)-1]]
+	} else {
+		want = fmt.Sprintf("%q", want)
+	}
+	if pos >= len(strings.TrimRightFunc(line, unicode.IsSpace)) {
+		return &ParseError{Col: pos + 1, Err: fmt.Errorf("line ends, expected %s", want)}
+	}
+	return &ParseError{Col: pos + 1, Token: wordAt(line, pos), Err: fmt.Errorf("expected %s", want)}
+}
+
+// Get the values to be stored into from the arguments to Match: either one
+// pointer per field, or a pointer to a struct with one field per field
+func (p *Pattern) targets(dst []any) ([]reflect.Value, error) {
+	var vals []reflect.Value
+	if len(dst) == 1 {
+		v := reflect.ValueOf(dst[0])
+		if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct {
+			v = v.Elem()
+			for i := 0; i < v.NumField(); i++ {
+				if !v.Field(i).CanSet() {
+					return nil, fmt.Errorf("pattern %q: field %s of %s is not exported", p.text, v.Type().Field(i).Name, v.Type())
+				}
+				vals = append(vals, v.Field(i))
+			}
+		}
+	}
+	if vals == nil {
+		for _, d := range dst {
+			v := reflect.ValueOf(d)
+			if v.Kind() != reflect.Pointer || v.IsNil() {
+				return nil, fmt.Errorf("pattern %q: %T is not a pointer", p.text, d)
+			}
+			vals = append(vals, v.Elem())
+		}
+	}
+	if len(vals) != p.nvals {
+		return nil, fmt.Errorf("pattern %q has %d fields, given %d to store them in", p.text, p.nvals, len(vals))
+	}
+	return vals, nil
+}
+
+// Convert the text of a field, and store it
+func setValue(v reflect.Value, kind, text string) error {
+	switch {
+	case kind == "int" && (v.Kind() == reflect.Int || v.Kind() == reflect.Int64):
+		n, err := ParseInt64(text)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case (kind == "word" || kind == "str") && v.Kind() == reflect.String:
+		v.SetString(text)
+	case kind == "ints" && v.Type() == reflect.TypeOf([]int{}):
+		nums, err := ParseInts(text, ",")
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(nums))
+	case kind == "words" && v.Type() == reflect.TypeOf([]string{}):
+		words := strings.Split(text, ",")
+		for i := range words {
+			words[i] = strings.TrimSpace(words[i])
+		}
+		v.Set(reflect.ValueOf(words))
+	default:
+		return fmt.Errorf("cannot store {%s} in a %s", kind, v.Type())
+	}
+	return nil
+}
+
+// Space or tab, i.e., what a space in a pattern matches
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r'
+}
+
+// The word starting at a position in a line, for error messages
+func wordAt(line string, pos int) string {
+	w := line[pos:]
+	if i := strings.IndexFunc(w, unicode.IsSpace); i == 0 {
+		w = w[:1]
+	} else if i > 0 {
+		w = w[:i]
+	}
+	return w
+}
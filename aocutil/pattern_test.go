@@ -0,0 +1,68 @@
+// Unit tests for line patterns
+
+package aocutil
+
+import (
+	"testing"
+)
+
+func TestPatternMatch(t *testing.T) {
+
+	// Separate values, with extra spaces in the line
+	p := MustPattern("Sensor at x={int}, y={int}: closest beacon is at x={int}, y={int}")
+	var sx, sy, bx, by int
+	err := p.Match("Sensor at x=2,  y=-18: closest beacon is at x=-2, y=15 ", &sx, &sy, &bx, &by)
+	if err != nil || sx != 2 || sy != -18 || bx != -2 || by != 15 {
+		t.Errorf("Match gives %d %d %d %d, %v", sx, sy, bx, by, err)
+	}
+
+	// A struct, lists, and skipped words
+	var valve struct {
+		Name  string
+		Rate  int64
+		Conns []string
+	}
+	p = MustPattern("Valve {word} has flow rate={int}; {_} {_} to {_} {words}")
+	err = p.Match("Valve BB has flow rate=13; tunnels lead to valves CC, AA", &valve)
+	if err != nil || valve.Name != "BB" || valve.Rate != 13 || !Same(valve.Conns, []string{"CC", "AA"}) {
+		t.Errorf("Match gives %+v, %v", valve, err)
+	}
+	var items []int
+	err = MustPattern("Starting items: {ints}").Match("  Starting items: 79, 98", &items)
+	if err != nil || !Same(items, []int{79, 98}) {
+		t.Errorf("Match gives %v, %v", items, err)
+	}
+}
+
+func TestPatternErrors(t *testing.T) {
+	p := MustPattern("Sensor at x={int}, y={int}")
+	var x, y int
+	tests := []struct{ line, msg string }{
+		{"Sensor at x=2; y=3", `3:14: ";": expected ", y="`},
+		{"Sensor at x=q, y=3", `3:13: "q,": expected integer`},
+		{"Sensor at x=2, y=-", `3:18: "-": invalid integer`},
+		{"Sensor at x=2, y=3 and more", `3:20: "and": unexpected text at end of line`},
+		{"Sensor at x=2", `3:14: line ends, expected ", y="`},
+	}
+	for _, test := range tests {
+		err := AtLine(p.Match(test.line, &x, &y), 3, test.line)
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("Match(%q) gives error %v, want %s", test.line, err, test.msg)
+		}
+	}
+
+	// Wrong things to store values in
+	if err := p.Match("Sensor at x=2, y=3", &x); err == nil {
+		t.Errorf("Match with too few values should fail")
+	}
+	var s string
+	if err := p.Match("Sensor at x=2, y=3", &x, &s); err == nil {
+		t.Errorf("Match of an integer into a string should fail")
+	}
+	if _, err := NewPattern("x={int"); err == nil {
+		t.Errorf("NewPattern with unclosed field should fail")
+	}
+	if _, err := NewPattern("items: {ints}{str}"); err == nil {
+		t.Errorf("NewPattern with fields with nothing between should fail")
+	}
+}
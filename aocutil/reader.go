@@ -0,0 +1,217 @@
+// Streaming input, for inputs that are too big to read into memory all at
+// once: iterate over lines, or over records (groups of lines separated by
+// blank lines), or read one byte at a time. Input can come from a file,
+// standard input ("-"), or a gzip-compressed file.
+//
+// Typical use, e.g., for the groups of numbers in Day 1:
+//
+//	in := aocutil.NewReader(r)
+//	for in.NextRecord() {
+//		for i, l := range in.Record() {
+//			n, err := aocutil.ParseInt(l)
+//			if err != nil {
+//				return in.AtLine(err, i)
+//			}
+//			...
+//		}
+//	}
+//	return in.Err()
+//
+// AK, Dec 2022
+
+package aocutil
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+)
+
+// Open an input file for reading, standard input if the name is "-", and
+// decompressing it if it is gzipped
+func Open(fname string) (io.ReadCloser, error) {
+	var f io.ReadCloser = os.Stdin
+	if fname != "-" {
+		var err error
+		if f, err = os.Open(fname); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check for the gzip "magic number" at the start
+	br := bufio.NewReader(f)
+	magic, _ := br.Peek(2)
+	if len(magic) < 2 || magic[0] != 0x1f || magic[1] != 0x8b {
+		return readCloser{br, f}, nil
+	}
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return readCloser{zr, multiCloser{zr, f}}, nil
+}
+
+// A reader, with what needs to be closed when finished with it
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// Close more than one thing, e.g., a gzip reader and the file under it
+type multiCloser []io.Closer
+
+func (mc multiCloser) Close() error {
+	var err error
+	for _, c := range mc {
+		if e := c.Close(); err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Reader for streaming input, a line or a record at a time
+type Reader struct {
+	br     *bufio.Reader
+	line   int      // number of the line last read, starting at 1
+	text   string   // the line last read
+	record []string // the record last read
+	first  int      // line number of the first line of the record
+	col    int      // column of the byte last read by NextByte
+	err    error    // error reading, other than io.EOF
+	eof    bool     // true when there is nothing more to read
+}
+
+// Create a streaming reader
+func NewReader(r io.Reader) *Reader {
+	return &Reader{br: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// Read the next line, return false at the end of the input or if there
+// was an error (check Err). Lines may be of any length, and any trailing
+// carriage return is removed.
+func (r *Reader) Next() bool {
+	if r.eof {
+		return false
+	}
+	line, err := r.br.ReadString('\n')
+	if err != nil {
+		r.eof = true
+		if err != io.EOF {
+			r.err = err
+			return false
+		}
+		if len(line) == 0 {
+			return false
+		}
+	}
+	r.line++
+	r.text = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	return true
+}
+
+// The line last read by Next
+func (r *Reader) Text() string {
+	return r.text
+}
+
+// Line number of the line last read, starting at 1
+func (r *Reader) Line() int {
+	return r.line
+}
+
+// Read the next record, i.e., the next group of non-blank lines, skipping
+// any blank lines before it. Returns false at the end of the input or if
+// there was an error (check Err).
+func (r *Reader) NextRecord() bool {
+	r.record = nil
+	for r.Next() {
+		if len(strings.TrimSpace(r.text)) == 0 {
+			if len(r.record) > 0 {
+				return true
+			}
+			continue
+		}
+		if len(r.record) == 0 {
+			r.first = r.line
+		}
+		r.record = append(r.record, r.text)
+	}
+	return len(r.record) > 0 && r.err == nil
+}
+
+// The lines of the record last read by NextRecord
+func (r *Reader) Record() []string {
+	return r.record
+}
+
+// Line number of the i'th line of the record last read (from 0)
+func (r *Reader) RecordLine(i int) int {
+	return r.first + i
+}
+
+// Read the next byte, skipping over line ends, return false at the end of
+// the input or if there was an error (check Err)
+func (r *Reader) NextByte() (byte, bool) {
+	for !r.eof {
+		c, err := r.br.ReadByte()
+		if err != nil {
+			r.eof = true
+			if err != io.EOF {
+				r.err = err
+			}
+			break
+		}
+		if c == '\n' {
+			r.line++
+			r.col = 0
+			continue
+		}
+		r.col++
+		if c != '\r' {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// Make an error about the byte last read by NextByte, with its position
+func (r *Reader) ByteError(c byte, err error) error {
+	return &ParseError{Line: r.line + 1, Col: r.col, Token: string(c), Err: err}
+}
+
+// Error reading the input, nil if none (reaching the end is not an error)
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Make an error about the current line (or the first line of the current
+// record, if any)
+func (r *Reader) Errorf(format string, args ...any) error {
+	return LineErrorf(r.errLine(0), format, args...)
+}
+
+// Add the line number of the current line (or the i'th line of the current
+// record, if any) to an error, see AtLine
+func (r *Reader) AtLine(err error, i int) error {
+	return AtLine(err, r.errLine(i), r.errText(i))
+}
+
+// Line number for an error, in the record if there is one
+func (r *Reader) errLine(i int) int {
+	if len(r.record) > 0 {
+		return r.RecordLine(i)
+	}
+	return r.line
+}
+
+// Text of the line for an error, in the record if there is one
+func (r *Reader) errText(i int) string {
+	if i < len(r.record) {
+		return r.record[i]
+	}
+	return r.text
+}
@@ -0,0 +1,82 @@
+// Unit tests for the streaming reader
+
+package aocutil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReaderRecords(t *testing.T) {
+
+	// Records separated by one or more blank lines, with CRLF line ends
+	// and no newline at the end
+	in := NewReader(strings.NewReader("\n1\r\n2\n\n\n3\n4\n5"))
+	var records [][]string
+	var first []int
+	for in.NextRecord() {
+		records = append(records, in.Record())
+		first = append(first, in.RecordLine(0))
+	}
+	if in.Err() != nil || len(records) != 2 || !Same(records[0], []string{"1", "2"}) ||
+		!Same(records[1], []string{"3", "4", "5"}) || !Same(first, []int{2, 6}) {
+		t.Errorf("NextRecord gives %q starting at lines %v, %v", records, first, in.Err())
+	}
+
+	// Errors in a record give the line in the record
+	in = NewReader(strings.NewReader("1\n\n2\nx=7q\n"))
+	in.NextRecord()
+	in.NextRecord()
+	_, err := ParseInt("7q")
+	if s := in.AtLine(err, 1).Error(); s != `4:3: "7q": invalid integer` {
+		t.Errorf("Error message is %s", s)
+	}
+}
+
+func TestReaderBytes(t *testing.T) {
+	in := NewReader(strings.NewReader("ab\ncd\n"))
+	var got []byte
+	for c, ok := in.NextByte(); ok; c, ok = in.NextByte() {
+		got = append(got, c)
+		if c == 'd' {
+			if s := in.ByteError(c, ErrFormat).Error(); s != `2:2: "d": invalid format` {
+				t.Errorf("Error message is %s", s)
+			}
+		}
+	}
+	if string(got) != "abcd" {
+		t.Errorf("NextByte gives %q", got)
+	}
+}
+
+func TestOpenGzip(t *testing.T) {
+
+	// Write the same text plain and gzipped, both should read the same
+	text := "line 1\nline 2\n"
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(text))
+	zw.Close()
+	files := map[string][]byte{"plain.txt": []byte(text), "input.txt.gz": buf.Bytes()}
+	for name, data := range files {
+		fname := filepath.Join(dir, name)
+		if err := os.WriteFile(fname, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := Open(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(f)
+		f.Close()
+		if err != nil || string(got) != text {
+			t.Errorf("%s reads as %q, %v", name, got, err)
+		}
+	}
+}
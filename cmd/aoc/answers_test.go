@@ -0,0 +1,148 @@
+// Regression tests: run every day on each of its input files, and check the
+// answers against those in the day's answers.json. Answers that take a long
+// time are only checked with -slow, e.g.,
+//
+//	go test ./cmd/aoc -slow -timeout 30m
+//
+// A new day with no answers yet, or with parts that still return
+// aoc.ErrNoSolution, is skipped.
+//
+// After a deliberate change to an answer, rewrite the answers files with the
+// answers the solvers now give, and check the differences before committing:
+//
+//	go test ./cmd/aoc -update
+//
+// AK, Dec 2022
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+)
+
+var slow = flag.Bool("slow", false, "also check answers that take a long time")
+var update = flag.Bool("update", false, "rewrite answers files with the answers given")
+
+// Root of the repository, relative to this directory
+const root = "../.."
+
+func TestAnswers(t *testing.T) {
+	for _, day := range aoc.Days() {
+		day := day
+		t.Run(aoc.DayDir(day), func(t *testing.T) {
+			t.Parallel()
+			checkDay(t, day)
+		})
+	}
+}
+
+// Check all the expected answers for one day
+func checkDay(t *testing.T, day int) {
+	dir := filepath.Join(root, aoc.DayDir(day))
+	fname := filepath.Join(dir, aoc.AnswersFile)
+	answers, err := aoc.ReadAnswers(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) == 0 {
+		t.Skipf("%s: no expected answers yet", fname)
+	}
+
+	// Parse each input file just once, in the order they first appear
+	var inputs []string
+	byInput := map[string][]int{} // indexes of the answers for each input
+	for i, a := range answers {
+		if _, ok := byInput[a.Input]; !ok {
+			inputs = append(inputs, a.Input)
+		}
+		byInput[a.Input] = append(byInput[a.Input], i)
+	}
+	changed := false
+	unsolved := []string{} // parts not solved yet, skipped
+	for _, input := range inputs {
+
+		// Skip the input altogether if all its answers are slow
+		todo := []int{}
+		for _, i := range byInput[input] {
+			if !answers[i].Slow || *slow || *update {
+				todo = append(todo, i)
+			}
+		}
+		if len(todo) == 0 {
+			t.Logf("%s: skipped slow answers (use -slow)", input)
+			continue
+		}
+
+		// Parse the input (with the settings for the sample, if it is one)
+		s, _ := aoc.New(day)
+		if sm, ok := s.(aoc.Sampler); ok && input == "sample.txt" {
+			sm.UseSample()
+		}
+		f, err := aocutil.Open(filepath.Join(dir, input))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		err = s.Parse(f)
+		f.Close()
+		if err != nil {
+			t.Error(aocutil.InFile(err, input))
+			continue
+		}
+
+		// Solve each part, and compare with the expected answer
+		for _, i := range todo {
+			a := &answers[i]
+			solve := s.Part1
+			if a.Part == 2 {
+				solve = s.Part2
+			}
+			ans, err := solve()
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+				ans = ""
+			}
+			if errors.Is(err, aoc.ErrNoSolution) {
+				unsolved = append(unsolved, fmt.Sprintf("%s, part %d", input, a.Part))
+			} else if *update {
+				if ans != a.Answer || errMsg != a.Error {
+					a.Answer, a.Error = ans, errMsg
+					changed = true
+				}
+			} else if errMsg != a.Error {
+				t.Errorf("%s, part %d: got error %q, want %q", input, a.Part, errMsg, a.Error)
+			} else if ans != a.Answer {
+				t.Errorf("%s, part %d: got %s, want %s", input, a.Part, show(ans), show(a.Answer))
+			}
+		}
+	}
+
+	// Rewrite the answers file if any have changed
+	if changed {
+		if err := aoc.WriteAnswers(fname, answers); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("%s: updated", fname)
+	}
+	if len(unsolved) > 0 {
+		t.Skipf("no solution yet for %s", strings.Join(unsolved, "; "))
+	}
+}
+
+// Show an answer in an error message, on separate lines if it has more
+// than one (e.g., the screen for Day 10)
+func show(ans string) string {
+	if strings.Contains(ans, "\n") {
+		return "\n" + ans + "\n"
+	}
+	return ans
+}
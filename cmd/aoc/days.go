@@ -0,0 +1,31 @@
+// Code generated by make_day; DO NOT EDIT.
+
+package main
+
+import (
+	_ "adventofcode2022/day01"
+	_ "adventofcode2022/day02"
+	_ "adventofcode2022/day03"
+	_ "adventofcode2022/day04"
+	_ "adventofcode2022/day05"
+	_ "adventofcode2022/day06"
+	_ "adventofcode2022/day07"
+	_ "adventofcode2022/day08"
+	_ "adventofcode2022/day09"
+	_ "adventofcode2022/day10"
+	_ "adventofcode2022/day11"
+	_ "adventofcode2022/day12"
+	_ "adventofcode2022/day13"
+	_ "adventofcode2022/day14"
+	_ "adventofcode2022/day15"
+	_ "adventofcode2022/day16"
+	_ "adventofcode2022/day17"
+	_ "adventofcode2022/day18"
+	_ "adventofcode2022/day19"
+	_ "adventofcode2022/day20"
+	_ "adventofcode2022/day21"
+	_ "adventofcode2022/day22"
+	_ "adventofcode2022/day23"
+	_ "adventofcode2022/day24"
+	_ "adventofcode2022/day25"
+)
@@ -0,0 +1,268 @@
+// Advent of Code 2022 runner: one program to run the solution for any day,
+// on any input file, e.g.
+//
+//	aoc run 16 --input day16/sample.txt --part 2
+//	aoc run all
+//	zcat big.txt.gz | aoc run 1 --input -
+//	aoc run 21 --opt num=big
+//	aoc show 21 dot --sample
+//	aoc show 16 timeline2 --opt actors=3
+//
+// By default, runs both parts on the day's input.txt (or sample.txt with
+// --sample), looked for in the day's directory under the current directory.
+// The input file may be gzipped, and "-" reads standard input.
+//
+// AK, Dec 2022
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "run":
+		if err := run(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "aoc:", err)
+			os.Exit(1)
+		}
+	case "show":
+		if err := showInput(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "aoc:", err)
+			os.Exit(1)
+		}
+	case "list":
+		for _, d := range aoc.Days() {
+			fmt.Println(d)
+		}
+	default:
+		usage()
+	}
+}
+
+// Show how to use the program, and exit
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  aoc run <day|all> [--input file] [--sample] [--part 1|2] [--opt name=value]...")
+	fmt.Fprintln(os.Stderr, "  aoc show <day> <format> [--input file] [--sample] [--opt name=value]...")
+	fmt.Fprintln(os.Stderr, "  aoc list")
+	os.Exit(2)
+}
+
+// The "run" subcommand: parse arguments, and run the day(s) requested
+func run(args []string) error {
+
+	// Parse flags, which may come before or after the day
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	input := fs.String("input", "", "input file, may be gzipped, - for stdin (default: input.txt in the day's directory)")
+	sample := fs.Bool("sample", false, "use sample.txt instead of input.txt")
+	part := fs.Int("part", 0, "part to run, 1 or 2 (default: both)")
+	opts := optionFlag(fs)
+	var which string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		which = args[0]
+		args = args[1:]
+	}
+	fs.Parse(args)
+	if which == "" {
+		which = fs.Arg(0)
+	}
+	if which == "" {
+		return fmt.Errorf("missing day number (or \"all\")")
+	}
+	if *part < 0 || *part > 2 {
+		return fmt.Errorf("invalid part %d", *part)
+	}
+
+	// Run all the days, or just one
+	if which == "all" {
+		if *input != "" {
+			return fmt.Errorf("--input cannot be used with \"all\"")
+		}
+		if len(*opts) > 0 {
+			return fmt.Errorf("--opt cannot be used with \"all\"")
+		}
+		// Carry on after a day fails, and say which failed at the end
+		var failed []string
+		for _, d := range aoc.Days() {
+			if err := runDay(d, defaultInput(d, *sample), *sample, *part, nil); err != nil {
+				failed = append(failed, err.Error())
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%s", strings.Join(failed, "; "))
+		}
+		return nil
+	}
+	day, err := strconv.Atoi(which)
+	if err != nil {
+		return fmt.Errorf("invalid day %q", which)
+	}
+	fname := *input
+	if fname == "" {
+		fname = defaultInput(day, *sample)
+	}
+	return runDay(day, fname, *sample, *part, *opts)
+}
+
+// Run one or both parts of a day on an input file, with any options given
+// as name=value, showing the answers and how long each took, and return an
+// error saying which parts failed, if any
+func runDay(day int, fname string, sample bool, part int, opts []string) error {
+	s, ok := aoc.New(day)
+	if !ok {
+		return fmt.Errorf("no Go solution for day %d", day)
+	}
+	if err := setOptions(day, s, sample, opts); err != nil {
+		return err
+	}
+	f, err := aocutil.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := s.Parse(f); err != nil {
+		return fmt.Errorf("day %d: %w", day, aocutil.InFile(err, fname))
+	}
+	var failed []string
+	for p, solve := range []func() (string, error){s.Part1, s.Part2} {
+		if part != 0 && part != p+1 {
+			continue
+		}
+		t0 := time.Now()
+		ans, err := solve()
+		dt := time.Since(t0).Round(time.Millisecond)
+		if err != nil {
+			fmt.Printf("Day %d, Part %d: %v\n", day, p+1, err)
+			failed = append(failed, fmt.Sprint(p+1))
+		} else if strings.Contains(ans, "\n") {
+			fmt.Printf("Day %d, Part %d (%v):\n%s\n", day, p+1, dt, ans)
+		} else {
+			fmt.Printf("Day %d, Part %d (%v): %s\n", day, p+1, dt, ans)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("day %d: part %s failed", day, strings.Join(failed, " and "))
+	}
+	return nil
+}
+
+// Add the --opt flag, which may be repeated, to a set of flags, returning
+// the list of options given
+func optionFlag(fs *flag.FlagSet) *[]string {
+	var opts []string
+	fs.Func("opt", "set an option for the day, as name=value (may be repeated)", func(s string) error {
+		if !strings.Contains(s, "=") {
+			return fmt.Errorf("option %q is not name=value", s)
+		}
+		opts = append(opts, s)
+		return nil
+	})
+	return &opts
+}
+
+// Set options given as name=value on a day's solver, after its settings
+// for the sample if that is what it is to run on
+func setOptions(day int, s aoc.Solver, sample bool, opts []string) error {
+	if sm, ok := s.(aoc.Sampler); ok && sample {
+		sm.UseSample()
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+	o, ok := s.(aoc.Optioner)
+	if !ok {
+		return fmt.Errorf("day %d has no options", day)
+	}
+	for _, opt := range opts {
+		name, value, _ := strings.Cut(opt, "=")
+		if err := o.SetOption(name, value); err != nil {
+			return fmt.Errorf("day %d: %w", day, err)
+		}
+	}
+	return nil
+}
+
+// The "show" subcommand: parse a day's input, and show it in one of the
+// formats the day offers (e.g., as a Graphviz graph)
+func showInput(args []string) error {
+
+	// Parse flags, which may come before or after the day and format
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	input := fs.String("input", "", "input file, may be gzipped, - for stdin (default: input.txt in the day's directory)")
+	sample := fs.Bool("sample", false, "use sample.txt instead of input.txt")
+	opts := optionFlag(fs)
+	var words []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		words = append(words, args[0])
+		args = args[1:]
+	}
+	fs.Parse(args)
+	words = append(words, fs.Args()...)
+	if len(words) != 2 {
+		return fmt.Errorf("need a day number and a format")
+	}
+	day, err := strconv.Atoi(words[0])
+	if err != nil {
+		return fmt.Errorf("invalid day %q", words[0])
+	}
+	format := words[1]
+
+	// Find the day, and check that it can show the input that way
+	s, ok := aoc.New(day)
+	if !ok {
+		return fmt.Errorf("no Go solution for day %d", day)
+	}
+	sh, ok := s.(aoc.Shower)
+	if !ok {
+		return fmt.Errorf("day %d has nothing to show", day)
+	}
+	if !aocutil.In(format, sh.Formats()) {
+		return fmt.Errorf("day %d can't show %q, only %s", day, format, strings.Join(sh.Formats(), ", "))
+	}
+	if err := setOptions(day, s, *sample, *opts); err != nil {
+		return err
+	}
+
+	// Parse the input, and show it
+	fname := *input
+	if fname == "" {
+		fname = defaultInput(day, *sample)
+	}
+	f, err := aocutil.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := s.Parse(f); err != nil {
+		return fmt.Errorf("day %d: %w", day, aocutil.InFile(err, fname))
+	}
+	return sh.Show(os.Stdout, format)
+}
+
+// Default input file for a day, in the day's directory (or the current
+// directory if we are already in the day's directory)
+func defaultInput(day int, sample bool) string {
+	fname := "input.txt"
+	if sample {
+		fname = "sample.txt"
+	}
+	dir := aoc.DayDir(day)
+	if cwd, err := os.Getwd(); err == nil && filepath.Base(cwd) == dir {
+		dir = "."
+	}
+	return filepath.Join(dir, fname)
+}
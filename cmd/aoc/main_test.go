@@ -0,0 +1,26 @@
+// Unit tests for the aoc command
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A part that fails makes the run fail, after running the other part
+func TestRunDayFails(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "monkeys.txt")
+	monkeys := "root: aaaa + bbbb\naaaa: humn - cccc\nbbbb: 9223372036854775807\ncccc: 1\nhumn: 0\n"
+	if err := os.WriteFile(fname, []byte(monkeys), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := runDay(21, fname, false, 0, nil)
+	if err == nil || !strings.Contains(err.Error(), "day 21: part 2 failed") {
+		t.Errorf("got %v, want part 2 to fail", err)
+	}
+	if err := runDay(21, fname, false, 1, nil); err != nil {
+		t.Errorf("part 1 alone: %v", err)
+	}
+}
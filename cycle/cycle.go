@@ -0,0 +1,203 @@
+// Package cycle finds where a long-running simulation starts repeating
+// itself, so that its result after any number of steps can be worked out
+// without running it that long, e.g., the height of the tower of rocks in
+// Day 17 after 1 000 000 000 000 rocks.
+//
+// A simulation is given as a start state, a step function, and a key
+// function that identifies a state (two states with the same key must
+// have the same future, e.g., the shape of the top of the tower, and the
+// next rock and gas burst). Counters are values that change as the
+// simulation runs (e.g., the height), which are extrapolated from how
+// much they change in each cycle.
+//
+//	sim := cycle.Sim[*tower, fingerprint]{Start: t, Step: drop, Key: key,
+//		Counters: func(t *tower) []int64 { return []int64{t.height} }}
+//	c, err := sim.FindMap()
+//	height := c.At(1000000000000)[0]
+//
+// There are three ways of finding the cycle: FindMap remembers the key of
+// every state until one comes up again (fastest, and the step function may
+// change the state in place), while FindFloyd and FindBrent only keep a
+// couple of states (for when there are too many steps before the cycle to
+// remember them all), but need a step function that returns a new state
+// without changing the one it is given.
+//
+// AK, Dec 2022
+
+package cycle
+
+import (
+	"errors"
+)
+
+// No cycle found within the limit on the number of steps
+var ErrNoCycle = errors.New("no cycle found")
+
+// A simulation to find the cycle of
+type Sim[S any, K comparable] struct {
+	Start    S               // the state before the first step
+	Step     func(S) S       // the state after the next step
+	Key      func(S) K       // identifies a state, same key means same future
+	Counters func(S) []int64 // values to extrapolate (optional), e.g., height
+	Limit    int             // give up after this many steps (0 for no limit)
+}
+
+// A cycle found in a simulation, with the counters for the steps up to
+// the end of the first cycle, from which any later step can be worked out
+type Cycle struct {
+	Prefix int       // steps before the cycle starts
+	Length int       // steps in each cycle
+	Values [][]int64 // counters after 0 to Prefix+Length steps
+	Deltas []int64   // how much each counter changes in one cycle
+}
+
+// Find the cycle by remembering the key of every state, until one comes up
+// again. The step function may change the state in place.
+func (sim *Sim[S, K]) FindMap() (*Cycle, error) {
+	seen := map[K]int{} // step at which each key was first seen
+	c := &Cycle{}
+	s := sim.Start
+	for n := 0; sim.Limit == 0 || n <= sim.Limit; n++ {
+		c.Values = append(c.Values, sim.counters(s))
+		k := sim.Key(s)
+		if first, ok := seen[k]; ok {
+			c.Prefix, c.Length = first, n-first
+			c.findDeltas()
+			return c, nil
+		}
+		seen[k] = n
+		s = sim.Step(s)
+	}
+	return nil, ErrNoCycle
+}
+
+// Find the cycle with Floyd's "tortoise and hare" algorithm: the hare
+// moves two steps for each of the tortoise's, until they meet in the
+// cycle. The step function must not change the state it is given.
+func (sim *Sim[S, K]) FindFloyd() (*Cycle, error) {
+
+	// Find a meeting point, some number of whole cycles from the start
+	tortoise, hare := sim.Step(sim.Start), sim.Step(sim.Step(sim.Start))
+	steps := 1
+	for sim.Key(tortoise) != sim.Key(hare) {
+		if sim.Limit > 0 && steps > sim.Limit {
+			return nil, ErrNoCycle
+		}
+		tortoise = sim.Step(tortoise)
+		hare = sim.Step(sim.Step(hare))
+		steps++
+	}
+
+	// Start of the cycle: move from the start and the meeting point at
+	// the same speed until they meet
+	prefix := 0
+	tortoise = sim.Start
+	for sim.Key(tortoise) != sim.Key(hare) {
+		tortoise = sim.Step(tortoise)
+		hare = sim.Step(hare)
+		prefix++
+	}
+
+	// Length of the cycle: go round it once
+	length := 1
+	for hare = sim.Step(tortoise); sim.Key(tortoise) != sim.Key(hare); hare = sim.Step(hare) {
+		length++
+	}
+	return sim.record(prefix, length), nil
+}
+
+// Find the cycle with Brent's algorithm: the hare moves ahead one step at
+// a time, and the tortoise jumps to it at every power of two, which finds
+// the length of the cycle directly. The step function must not change the
+// state it is given.
+func (sim *Sim[S, K]) FindBrent() (*Cycle, error) {
+
+	// Length of the cycle: only the key of the tortoise is needed
+	power, length := 1, 1
+	tortoise := sim.Key(sim.Start)
+	hare := sim.Step(sim.Start)
+	steps := 1
+	for tortoise != sim.Key(hare) {
+		if sim.Limit > 0 && steps > sim.Limit {
+			return nil, ErrNoCycle
+		}
+		if power == length {
+			tortoise = sim.Key(hare)
+			power *= 2
+			length = 0
+		}
+		hare = sim.Step(hare)
+		length++
+		steps++
+	}
+
+	// Start of the cycle: start one cycle apart, and move both at the
+	// same speed until they meet
+	behind, ahead := sim.Start, sim.Start
+	for i := 0; i < length; i++ {
+		ahead = sim.Step(ahead)
+	}
+	prefix := 0
+	for sim.Key(behind) != sim.Key(ahead) {
+		behind = sim.Step(behind)
+		ahead = sim.Step(ahead)
+		prefix++
+	}
+	return sim.record(prefix, length), nil
+}
+
+// Run the simulation again up to the end of the first cycle, recording the
+// counters (for Floyd and Brent, which do not keep the states as they go)
+func (sim *Sim[S, K]) record(prefix, length int) *Cycle {
+	c := &Cycle{Prefix: prefix, Length: length}
+	s := sim.Start
+	for n := 0; n <= prefix+length; n++ {
+		if n > 0 {
+			s = sim.Step(s)
+		}
+		c.Values = append(c.Values, sim.counters(s))
+	}
+	c.findDeltas()
+	return c
+}
+
+// The counters of a state, none if there is no Counters function
+func (sim *Sim[S, K]) counters(s S) []int64 {
+	if sim.Counters == nil {
+		return nil
+	}
+	return sim.Counters(s)
+}
+
+// Work out how much each counter changes in one cycle
+func (c *Cycle) findDeltas() {
+	first, last := c.Values[c.Prefix], c.Values[c.Prefix+c.Length]
+	c.Deltas = make([]int64, len(first))
+	for i := range first {
+		c.Deltas[i] = last[i] - first[i]
+	}
+}
+
+// The step in the first run through the cycle (or the prefix) whose state
+// is the same as after n steps
+func (c *Cycle) Index(n int64) int {
+	if n <= int64(c.Prefix) {
+		return int(n)
+	}
+	return c.Prefix + int((n-int64(c.Prefix))%int64(c.Length))
+}
+
+// The counters after n steps, extrapolated from the counters at the same
+// point in the first cycle, plus the change in each whole cycle since then
+func (c *Cycle) At(n int64) []int64 {
+	i := c.Index(n)
+	cycles := int64(0)
+	if n > int64(c.Prefix) {
+		cycles = (n - int64(c.Prefix)) / int64(c.Length)
+	}
+	vals := make([]int64, len(c.Values[i]))
+	for j, v := range c.Values[i] {
+		vals[j] = v + cycles*c.Deltas[j]
+	}
+	return vals
+}
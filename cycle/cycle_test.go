@@ -0,0 +1,95 @@
+// Unit tests for the cycle package
+
+package cycle
+
+import (
+	"errors"
+	"testing"
+)
+
+// A simple simulation: x goes to x*x+1 mod m, with a counter that adds up
+// the values of x along the way
+type state struct {
+	x, sum int64
+}
+
+func testSim(start, m int64) Sim[state, int64] {
+	return Sim[state, int64]{
+		Start:    state{start, 0},
+		Step:     func(s state) state { x := (s.x*s.x + 1) % m; return state{x, s.sum + x} },
+		Key:      func(s state) int64 { return s.x },
+		Counters: func(s state) []int64 { return []int64{s.sum} },
+	}
+}
+
+// All three ways of finding the cycle agree, and extrapolating the counter
+// gives the same as running the simulation
+func TestFind(t *testing.T) {
+	for _, c := range []struct{ start, m int64 }{{3, 255}, {0, 1000}, {7, 9973}, {5, 1}} {
+		sim := testSim(c.start, c.m)
+		cm, err1 := sim.FindMap()
+		cf, err2 := sim.FindFloyd()
+		cb, err3 := sim.FindBrent()
+		if err1 != nil || err2 != nil || err3 != nil {
+			t.Fatalf("%v: errors %v, %v, %v", c, err1, err2, err3)
+		}
+		for _, other := range []*Cycle{cf, cb} {
+			if other.Prefix != cm.Prefix || other.Length != cm.Length || other.Deltas[0] != cm.Deltas[0] {
+				t.Errorf("%v: found %d+%d (%v) and %d+%d (%v)", c, cm.Prefix, cm.Length, cm.Deltas,
+					other.Prefix, other.Length, other.Deltas)
+			}
+		}
+
+		// Compare with running the simulation for a while
+		s := sim.Start
+		for n := int64(0); n < 3000; n++ {
+			if got := cm.At(n)[0]; got != s.sum {
+				t.Fatalf("%v: after %d steps, got %d instead of %d", c, n, got, s.sum)
+			}
+			if i := cm.Index(n); i > cm.Prefix+cm.Length {
+				t.Fatalf("%v: index of step %d is %d", c, n, i)
+			}
+			s = sim.Step(s)
+		}
+	}
+}
+
+// A known cycle: 0 1 2 3 4 5 6 7 3 4 5 6 7 ...
+func TestKnown(t *testing.T) {
+	sim := Sim[int, int]{
+		Start: 0,
+		Step: func(x int) int {
+			if x == 7 {
+				return 3
+			}
+			return x + 1
+		},
+		Key:      func(x int) int { return x },
+		Counters: func(x int) []int64 { return []int64{int64(x)} },
+	}
+	c, err := sim.FindBrent()
+	if err != nil || c.Prefix != 3 || c.Length != 5 || c.Deltas[0] != 0 {
+		t.Fatalf("found %+v, %v", c, err)
+	}
+	if got := c.At(1000000000003)[0]; got != 3 {
+		t.Errorf("state after 1000000000003 steps is %d instead of 3", got)
+	}
+}
+
+// A state changed in place, with a limit that is too low
+func TestLimit(t *testing.T) {
+	type counter struct{ n int }
+	sim := Sim[*counter, int]{
+		Start: &counter{},
+		Step:  func(c *counter) *counter { c.n = (c.n + 1) % 100; return c },
+		Key:   func(c *counter) int { return c.n },
+	}
+	c, err := sim.FindMap()
+	if err != nil || c.Prefix != 0 || c.Length != 100 {
+		t.Fatalf("found %+v, %v", c, err)
+	}
+	sim.Start, sim.Limit = &counter{}, 50
+	if _, err := sim.FindMap(); !errors.Is(err, ErrNoCycle) {
+		t.Errorf("got %v instead of ErrNoCycle", err)
+	}
+}
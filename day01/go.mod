@@ -1,3 +0,0 @@
-module day01
-
-go 1.19
@@ -5,41 +5,58 @@
 //
 // AK, 1 Dec 2022
 
-package main
+package day01
 
 import (
 	"fmt"
+	"io"
 	"sort"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
-func main() {
-
-	// Read the input file
-	//lines := readLines("sample.txt")
-	lines := readLines("input.txt")
-	fmt.Println(len(lines), "lines read")
-
-	// Add up groups of integers, separated by blank lines
-	nums := []int{} // list of totals
-	var tot int
-	for _, x := range lines {
-		if len(x) == 0 {
-			nums = append(nums, tot)
-			tot = 0
-		} else {
-			tot += atoi(x)
+func init() {
+	aoc.Register(1, func() aoc.Solver { return &solver{} })
+}
+
+// The parsed input: the 3 largest totals of the groups of numbers, largest
+// first (only these are kept, so the input can be of any size)
+type solver struct {
+	top []int
+}
+
+// Read the input, and add up groups of integers, separated by blank lines
+func (s *solver) Parse(r io.Reader) error {
+	in := aocutil.NewReader(r)
+	s.top = []int{}
+	for in.NextRecord() {
+		tot := 0
+		for i, x := range in.Record() {
+			n, err := aocutil.ParseInt(x)
+			if err != nil {
+				return in.AtLine(err, i)
+			}
+			tot += n
+		}
+		s.top = append(s.top, tot)
+		sort.Sort(sort.Reverse(sort.IntSlice(s.top)))
+		if len(s.top) > 3 {
+			s.top = s.top[:3]
 		}
 	}
-	nums = append(nums, tot)
+	return in.Err()
+}
 
-	// Part 1: report the largest total
-	fmt.Println("Part 1:", max(nums))
+// Part 1: report the largest total
+func (s *solver) Part1() (string, error) {
+	return fmt.Sprint(aocutil.Max(s.top)), nil
+}
 
-	// Part 2: sum of top 3
-	sort.Ints(nums)
-	tot = 0
-	for i := 0; i < 3; i++ {
-		tot += nums[len(nums)-1-i]
+// Part 2: sum of top 3
+func (s *solver) Part2() (string, error) {
+	if len(s.top) < 3 {
+		return "", fmt.Errorf("only %d groups of numbers", len(s.top))
 	}
-	fmt.Println("Part 2:", tot)
+	return fmt.Sprint(aocutil.Sum(s.top)), nil
 }
@@ -1,3 +0,0 @@
-module day02
-
-go 1.19
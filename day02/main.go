@@ -5,66 +5,87 @@
 //
 // AK, 2 Dec 2022
 
-package main
+package day02
 
 import (
 	"fmt"
-	"io/ioutil"
-	"strings"
+	"io"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
-func main() {
+func init() {
+	aoc.Register(2, func() aoc.Solver { return &solver{} })
+}
 
-	// Read the input file
-	//filename := "sample.txt"
-	filename := "input.txt"
-	data, _ := ioutil.ReadFile(filename)
-	lines := strings.Split(string(data), "\n")
-	fmt.Println(len(lines), "lines read")
+// The parsed input: how many times each round (e.g., "A Y") was played,
+// since there are only 9 different rounds (so the input can be of any size)
+type solver struct {
+	rounds map[string]int
+}
 
-	// Part 1: play game, assuming that:
-	// First col: A for Rock, B for Paper, and C for Scissors
-	// Second col:  X for Rock, Y for Paper, and Z for Scissors
-	score := 0
-	opShapes := map[byte]string{'A': "Rock", 'B': "Paper", 'C': "Scissors"}
-	myShapes := map[byte]string{'X': "Rock", 'Y': "Paper", 'Z': "Scissors"}
-	shapeScore := map[string]int{"Rock": 1, "Paper": 2, "Scissors": 3}
-	for _, l := range lines {
-		if len(l) < 3 {
-			break
+// Shapes for each letter in the input, and score for each shape
+var opShapes = map[byte]string{'A': "Rock", 'B': "Paper", 'C': "Scissors"}
+var myShapes = map[byte]string{'X': "Rock", 'Y': "Paper", 'Z': "Scissors"}
+var shapeScore = map[string]int{"Rock": 1, "Paper": 2, "Scissors": 3}
+
+// Read the input, one round per line, each with two letters
+func (s *solver) Parse(r io.Reader) error {
+	in := aocutil.NewReader(r)
+	s.rounds = map[string]int{}
+	for in.Next() {
+		l := in.Text()
+		if len(l) == 0 {
+			continue
+		}
+		if len(l) != 3 || opShapes[l[0]] == "" || l[1] != ' ' || myShapes[l[2]] == "" {
+			return in.Errorf("invalid round: %q", l)
 		}
+		s.rounds[l]++
+	}
+	return in.Err()
+}
+
+// Part 1: play game, assuming that:
+// First col: A for Rock, B for Paper, and C for Scissors
+// Second col:  X for Rock, Y for Paper, and Z for Scissors
+// (15 for sample, 13809 with input)
+func (s *solver) Part1() (string, error) {
+	score := 0
+	for l, n := range s.rounds {
 		op := opShapes[l[0]]
 		me := myShapes[l[2]]
-		score += shapeScore[me]
+		score += shapeScore[me] * n
 		if op == me { // draw
-			score += 3
+			score += 3 * n
 		} else if defeats(me, op) {
-			score += 6
+			score += 6 * n
 		}
 	}
-	fmt.Println("Part 1 (15 for sample, 13809 with input):", score)
+	return fmt.Sprint(score), nil
+}
 
-	// Part 2: find the combination of shapes that results in the outcome
-	// predicted by second column:
-	// X means you need to lose,
-	// Y means you need to end the round in a draw, and
-	// Z means you need to win
-	score = 0
-	for _, l := range lines {
-		if len(l) < 3 {
-			break
-		}
+// Part 2: find the combination of shapes that results in the outcome
+// predicted by second column:
+// X means you need to lose,
+// Y means you need to end the round in a draw, and
+// Z means you need to win
+// (12 for sample, 12316 with input)
+func (s *solver) Part2() (string, error) {
+	score := 0
+	for l, n := range s.rounds {
 		op := opShapes[l[0]]
 		outcome := l[2] // X/Y/Z
 		myShape := outcomeShape(op, outcome)
-		score += shapeScore[myShape]
+		score += shapeScore[myShape] * n
 		if outcome == 'Y' { // draw
-			score += 3
+			score += 3 * n
 		} else if outcome == 'Z' { // win
-			score += 6
+			score += 6 * n
 		}
 	}
-	fmt.Println("Part 2 (12 for sample, 12316 with input):", score)
+	return fmt.Sprint(score), nil
 }
 
 // Rock defeats Scissors, Scissors defeats Paper, and Paper defeats Rock.
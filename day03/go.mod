@@ -1,3 +0,0 @@
-module day03
-
-go 1.19
@@ -5,47 +5,84 @@
 //
 // AK, 3 Dec 2022
 
-package main
+package day03
 
 import (
 	"fmt"
-	"io/ioutil"
-	"strings"
+	"io"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
-func main() {
+func init() {
+	aoc.Register(3, func() aoc.Solver { return &solver{} })
+}
 
-	// Read the input file
-	//lines := readLines("sample.txt")
-	lines := readLines("input.txt")
-	fmt.Println(len(lines), "lines read")
+// The parsed input: both parts are added up as the input is read, one
+// line (rucksack) at a time, so it can be of any size
+type solver struct {
+	part1, part2 int
+	nlines       int
+}
 
-	// Part 1: sum up common characters in the two halves of each line
-	tot := 0
-	for _, l := range lines {
+// Read the input one line at a time, each an even number of letters, and
+// add up the totals for both parts
+func (s *solver) Parse(r io.Reader) error {
+	input := aocutil.NewReader(r)
+	*s = solver{}
+	group := []string{} // group of up to 3 lines, for Part 2
+	for input.Next() {
+		l := input.Text()
+		if len(l)%2 != 0 {
+			return input.Errorf("odd number of items: %q", l)
+		}
+		for j := 0; j < len(l); j++ {
+			if cval(l[j]) == 0 {
+				return &aocutil.ParseError{Line: input.Line(), Col: j + 1, Token: l[j : j+1], Err: aocutil.ErrFormat}
+			}
+		}
+		s.nlines++
+
+		// Part 1: sum up common characters in the two halves of each line
 		a := l[:len(l)/2]      // left half of string
 		b := l[len(l)/2:]      // right half of string
 		isects := common(a, b) // common character(s)
-		tot += cval(isects[0])
-	}
-	fmt.Println("Part 1 (s/b 157):", tot)
+		if len(isects) == 0 {
+			return input.Errorf("no common character in %q", l)
+		}
+		s.part1 += cval(isects[0])
 
-	// Part 2: add up characters that are common to entire line
-	// in each group of 3 lines
-	tot = 0
-	for i := 0; i < len(lines); i += 3 {
-		l1 := lines[i]
-		l2 := lines[i+1]
-		l3 := lines[i+2]
-		c1 := common(l1, l2) // chars common to l1 and l2
-		for _, c := range c1 {
-			if in(c, l3) { // char that is also in l3
-				tot += cval(c)
-				break
+		// Part 2: add up characters that are common to entire line
+		// in each group of 3 lines
+		group = append(group, l)
+		if len(group) == 3 {
+			c1 := common(group[0], group[1]) // chars common to l1 and l2
+			for _, c := range c1 {
+				if in(c, group[2]) { // char that is also in l3
+					s.part2 += cval(c)
+					break
+				}
 			}
+			group = group[:0]
 		}
 	}
-	fmt.Println("Part 2 (s/b 70):", tot)
+	return input.Err()
+}
+
+// Part 1: sum up common characters in the two halves of each line
+// (s/b 157 for sample)
+func (s *solver) Part1() (string, error) {
+	return fmt.Sprint(s.part1), nil
+}
+
+// Part 2: add up characters that are common to entire line
+// in each group of 3 lines (s/b 70 for sample)
+func (s *solver) Part2() (string, error) {
+	if s.nlines%3 != 0 {
+		return "", fmt.Errorf("%d lines is not a multiple of 3", s.nlines)
+	}
+	return fmt.Sprint(s.part2), nil
 }
 
 // Get list of characters that are common to two strings
@@ -79,12 +116,3 @@ func cval(c byte) int {
 		return 0
 	}
 }
-
-// Read lines from the input file
-func readLines(filename string) []string {
-	data, err := ioutil.ReadFile(filename)
-	if err != nil {
-		panic(err)
-	}
-	return strings.Split(string(data), "\n")
-}
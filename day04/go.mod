@@ -1,3 +0,0 @@
-module day04
-
-go 1.19
@@ -6,43 +6,72 @@
 //
 // AK, 4 Dec 2022
 
-package main
+package day04
 
 import (
 	"fmt"
+	"io"
 	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
-func main() {
+func init() {
+	aoc.Register(4, func() aoc.Solver { return &solver{} })
+}
 
-	// Read the input file
-	//lines := readLines("sample.txt")
-	lines := readLines("input.txt")
-	fmt.Println(len(lines), "lines read")
+// The parsed input: just the counts for both parts, added up as the input
+// is read, so it can be of any size
+type solver struct {
+	contained int // pairs where one range fully contains the other
+	overlap   int // pairs that overlap at all
+}
 
-	// Each line consists of two ranges, split these
-	var part1, part2 int
-	for _, l := range lines {
+// Read the input one line at a time, each line consists of two ranges
+func (s *solver) Parse(r io.Reader) error {
+	in := aocutil.NewReader(r)
+	*s = solver{}
+	for in.Next() {
+		l := in.Text()
 
 		// Parse pair of ranges
 		ranges := strings.Split(l, ",")
-		range1 := strings.Split(ranges[0], "-")
-		range2 := strings.Split(ranges[1], "-")
-		r1 := []int{atoi(range1[0]), atoi(range1[1])}
-		r2 := []int{atoi(range2[0]), atoi(range2[1])}
+		if len(ranges) != 2 {
+			return in.Errorf("invalid pair of ranges: %q", l)
+		}
+		r1, err := aocutil.ParseInts(ranges[0], "-")
+		if err != nil {
+			return in.AtLine(err, 0)
+		}
+		r2, err := aocutil.ParseInts(ranges[1], "-")
+		if err != nil {
+			return in.AtLine(err, 0)
+		}
+		if len(r1) != 2 || len(r2) != 2 {
+			return in.Errorf("invalid pair of ranges: %q", l)
+		}
 
-		// // Part 1: In how many assignment pairs does one range fully contain the other?
+		// Part 1: does one range fully contain the other?
 		if (r1[0] >= r2[0] && r1[1] <= r2[1]) || (r2[0] >= r1[0] && r2[1] <= r1[1]) {
-			part1++
+			s.contained++
 		}
 
-		// Part 2:how many pairs overlap at all?
+		// Part 2: do they overlap at all?
 		if (r1[0] >= r2[0] && r1[0] <= r2[1]) || (r1[1] >= r2[0] && r1[1] <= r2[1]) ||
 			(r2[0] >= r1[0] && r2[0] <= r1[1]) || (r2[1] >= r1[0] && r2[1] <= r1[1]) {
-			part2++
+			s.overlap++
 		}
 	}
+	return in.Err()
+}
+
+// Part 1: In how many assignment pairs does one range fully contain the other?
+func (s *solver) Part1() (string, error) {
+	return fmt.Sprint(s.contained), nil
+}
 
-	fmt.Println("Part 1:", part1)
-	fmt.Println("Part 2:", part2)
+// Part 2: how many pairs overlap at all?
+func (s *solver) Part2() (string, error) {
+	return fmt.Sprint(s.overlap), nil
 }
@@ -1,3 +0,0 @@
-module day05
-
-go 1.19
@@ -5,82 +5,158 @@
 //
 // AK, 5 Dec 2022
 
-package main
+package day05
 
 import (
 	"fmt"
-	"io/ioutil"
-	"strconv"
+	"io"
 	"strings"
-)
-
-func main() {
-
-	// Read the input file
-	//filename := "sample.txt"  // uncomment filename required
-	filename := "input.txt"
-	data, _ := ioutil.ReadFile(filename)
-	lines := strings.Split(string(data), "\n")
-	fmt.Println(len(lines), "lines read")
 
-	// Stacks from the problem example, and from input data
-	// (transposed manually)
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+)
 
-	// Problem example:
-	//stack := [][]byte{[]byte("ZN"), []byte("MCD"), []byte("P")}
+func init() {
+	aoc.Register(5, func() aoc.Solver { return &solver{} })
+}
 
-	// From problem input (uncomment to use this)
-	stack := [][]byte{[]byte("WDGBHRV"), []byte("JNGCRF"), []byte("LSFHDNJ"),
-		[]byte("JDSV"), []byte("SHDRQWNV"), []byte("PGHCM"), []byte("FJBGLZHC"),
-		[]byte("SJR"), []byte("LGSRBNVM")}
+// The parsed input: the starting stacks of crates, and the instructions
+type solver struct {
+	stack [][]byte
+	moves []Move
+}
 
-	// Process each line: skip over stacks of crates, then follow instructions
-	// to move each item from top of one stack to top of another
-	crates := true // file starts with crates
-	for _, l := range lines {
+// An instruction to move crates from one stack to another
+type Move struct {
+	q, src, dst int
+}
 
-		// Skip over crates, until after first blank line
+// Read the input: stacks of crates, then instructions after the first blank
+// line
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	var moves []string
+	s.stack, moves, err = readStacks(lines)
+	if err != nil {
+		return err
+	}
+	first := len(lines) - len(moves) // line number of first instruction, less one
+	for i, l := range moves {
 		if len(l) == 0 {
-			crates = false
-			continue
-		}
-		if crates {
 			continue
 		}
 
 		// Parse an instruction, e.g., "move 1 from 2 to 1"
 		words := strings.Split(l, " ")
-		q := atoi(words[1])       // qty to move
-		src := atoi(words[3]) - 1 // source tower (adjust for zero indexing)
-		dst := atoi(words[5]) - 1 // destination
+		if len(words) != 6 || words[0] != "move" || words[2] != "from" || words[4] != "to" {
+			return aocutil.LineErrorf(first+i+1, "invalid instruction: %q", l)
+		}
+		var nums [3]int // qty to move, source tower, destination
+		for j := range nums {
+			nums[j], err = aocutil.ParseInt(words[1+j*2])
+			if err != nil {
+				return aocutil.AtLine(err, first+i+1, l)
+			}
+		}
+		q := nums[0]
+		src := nums[1] - 1 // adjust for zero indexing
+		dst := nums[2] - 1
+		if q < 0 || src < 0 || src >= len(s.stack) || dst < 0 || dst >= len(s.stack) {
+			return aocutil.LineErrorf(first+i+1, "invalid stack in instruction: %q", l)
+		}
+		s.moves = append(s.moves, Move{q, src, dst})
+	}
+	return nil
+}
+
+// Part 1: move crates one at a time
+func (s *solver) Part1() (string, error) {
+	return s.simulate(false)
+}
+
+// Part 2: move multiple crates at once instead of one at a time
+func (s *solver) Part2() (string, error) {
+	return s.simulate(true)
+}
+
+// Process each instruction, to move each item from top of one stack to top
+// of another, and return the top of each stack in the ending configuration
+func (s *solver) simulate(multiple bool) (string, error) {
+
+	// Work on a copy of the stacks
+	stack := make([][]byte, len(s.stack))
+	for i := range s.stack {
+		stack[i] = append([]byte{}, s.stack[i]...)
+	}
+
+	// Follow instructions to move each item from top of one stack to top of
+	// another
+	for _, m := range s.moves {
+		q, src, dst := m.q, m.src, m.dst
+		if q > len(stack[src]) {
+			return "", fmt.Errorf("cannot move %d crates from stack %d", q, src+1)
+		}
 
 		// Execute instruction, moving crates one at a time for Part 1
-		/*for i := 0; i < q; i++ {
-			o := stack[src][len(stack[src])-1]          // object to move
-			stack[dst] = append(stack[dst], o)          // add to destination
-			stack[src] = stack[src][:len(stack[src])-1] // remove from source
-		}*/
+		if !multiple {
+			for i := 0; i < q; i++ {
+				o := stack[src][len(stack[src])-1]          // object to move
+				stack[dst] = append(stack[dst], o)          // add to destination
+				stack[src] = stack[src][:len(stack[src])-1] // remove from source
+			}
+			continue
+		}
 
 		// For part 2, move multiple crates at once instead of one at a time
 		oo := stack[src][len(stack[src])-q:]        // object(s) to move
 		stack[dst] = append(stack[dst], oo...)      // add to destination
 		stack[src] = stack[src][:len(stack[src])-q] // remove from source
-
 	}
 
 	// For answer, show top of each stack in ending config
-	fmt.Print("Top of stacks: ")
+	tops := []byte{}
 	for i := 0; i < len(stack); i++ {
-		fmt.Print(string(stack[i][len(stack[i])-1]))
+		if len(stack[i]) > 0 {
+			tops = append(tops, stack[i][len(stack[i])-1])
+		}
 	}
+	return string(tops), nil
 }
 
-// Parse an integer, show message and return -1 if error
-func atoi(s string) int {
-	n, err := strconv.ParseInt(s, 10, 64)
-	if err != nil {
-		fmt.Println("Could not parse integer:", s)
-		n = -1
+// Parse the stacks of crates at the top of the input, e.g.
+//
+//	    [D]
+//	[N] [C]
+//	[Z] [M] [P]
+//	 1   2   3
+//
+// into bottom-to-top lists of crates (e.g., "ZN", "MCD", "P"), and return
+// these together with the lines that follow the first blank line
+func readStacks(lines []string) ([][]byte, []string, error) {
+
+	// Find the blank line, the line before it numbers the stacks
+	blank := 0
+	for blank < len(lines) && len(lines[blank]) > 0 {
+		blank++
+	}
+	if blank == 0 {
+		return nil, nil, fmt.Errorf("no stacks found")
+	}
+	nstacks := (len(lines[blank-1]) + 2) / 4
+
+	// Add the crates to each stack, starting from the bottom
+	stack := make([][]byte, nstacks)
+	for i := blank - 2; i >= 0; i-- {
+		l := lines[i]
+		for s := 0; s < nstacks; s++ {
+			c := 1 + s*4 // column with the crate letter
+			if c < len(l) && l[c] != ' ' {
+				stack[s] = append(stack[s], l[c])
+			}
+		}
 	}
-	return int(n)
+	return stack, lines[blank:], nil
 }
@@ -1,3 +0,0 @@
-module day06
-
-go 1.19
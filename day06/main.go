@@ -1,67 +1,83 @@
 // Advent of Code 2022, Day 06
 //
 // Look for first block of 4 (Part 1) or 14 (Part 2) non-repeating
-// characters in a string. Increased performance 10x by using concurrency
-// and simpler check for duplicate characters.
+// characters in a string. Increased performance 10x by using a simpler
+// check for duplicate characters. Reads the input as a stream, so the
+// message can be longer than fits in memory.
 //
 // AK, 6 Dec 2022
 
-package main
+package day06
 
 import (
 	"fmt"
-	"io/ioutil"
-)
+	"io"
 
-func main() {
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+)
 
-	// Read input from file
-	//data, _ := ioutil.ReadFile("input.txt")
-	data, _ := ioutil.ReadFile("day06_input10000")
-	msg := string(data)
+func init() {
+	aoc.Register(6, func() aoc.Solver { return &solver{} })
+}
 
-	// Concurrently look for markers of length 4 (Part 1) and 14 (Part 2),
-	// using a "channel" to allow concurrent processes to return results
-	// when ready (code will continue when both results are sent back)
-	c := make(chan int)       // create the channel
-	go marker(&msg, 4, c)     // run the 4-char check in background
-	go marker(&msg, 14, c)    // same for 14-char check
-	part1, part2 := <-c, <-c  // collect results when ready
-	fmt.Println(part1, part2) // show results (may be out of order!)
+// The parsed input: positions of the markers of length 4 and 14 (-1 if
+// not found)
+type solver struct {
+	marker4, marker14 int
 }
 
-// Find the position of the "marker", i.e., a block where n chars
-// are all different, send result to channel
-func marker(s *string, n int, c chan int) {
-	for i := n - 1; i < len(*s); i++ {
-		//substr := s[i-n+1 : i+1]  // get n-char substring
-		//if !duplicates2(substr) { // no duplicate chars?
-		if !duplicates3(s, i-n+1, i) { // no duplicate chars?
-			c <- i + 1 // send back position of marker
-			return
+// Read the input one character at a time, looking for both markers as we
+// go, so that the message can be of any length. Stops reading once both
+// markers have been found.
+func (s *solver) Parse(r io.Reader) error {
+	in := aocutil.NewReader(r)
+	s.marker4, s.marker14 = -1, -1
+	window := make([]byte, 0, 14) // the last 14 characters
+	for pos := 1; s.marker14 < 0; pos++ {
+		c, ok := in.NextByte()
+		if !ok {
+			break
+		}
+		if c < 'a' || c > 'z' {
+			return in.ByteError(c, aocutil.ErrFormat)
+		}
+		if len(window) == cap(window) {
+			copy(window, window[1:])
+			window = window[:len(window)-1]
+		}
+		window = append(window, c)
+		if s.marker4 < 0 && len(window) >= 4 && !duplicates(string(window[len(window)-4:])) {
+			s.marker4 = pos
+		}
+		if len(window) == 14 && !duplicates(string(window)) {
+			s.marker14 = pos
 		}
 	}
-	c <- -1 // no marker found
+	return in.Err()
 }
 
-// Check if a string contains any duplicate characters
-// (first implementation, slow because using map)
-func duplicates(s string) bool {
-	chars := map[byte]int{}
-	for i := 0; i < len(s); i++ {
-		c := s[i]
-		if chars[c] > 0 { // duplicate found
-			return true
-		}
-		chars[c] += 1 // remember we found this character
+// Part 1: look for a marker of length 4
+func (s *solver) Part1() (string, error) {
+	return showMarker(s.marker4, 4)
+}
+
+// Part 2: look for a marker of length 14
+func (s *solver) Part2() (string, error) {
+	return showMarker(s.marker14, 14)
+}
+
+// Show the position of a marker of length n, error if there is none
+func showMarker(m, n int) (string, error) {
+	if m < 0 {
+		return "", fmt.Errorf("no marker of length %d found", n)
 	}
-	return false
+	return fmt.Sprint(m), nil
 }
 
-// Check if a string contains any duplicate characters,
-// second and faster implementation using character comparisons inside
-// loops instead of map
-func duplicates2(s string) bool {
+// Check if a string contains any duplicate characters, comparing each
+// character with the ones before it (faster than a map for short strings)
+func duplicates(s string) bool {
 	for i := 0; i < len(s); i++ {
 		for j := 0; j < i; j++ {
 			if s[i] == s[j] {
@@ -71,18 +87,3 @@ func duplicates2(s string) bool {
 	}
 	return false
 }
-
-// Check if a string contains any duplicate characters,
-// third and even faster version that takes a pointer to a string,
-// rather than a new substring copy each time; i and j are the indexes
-// of the characters to be searched, zero-based and inclusive
-func duplicates3(s *string, i, j int) bool {
-	for a := i; a <= j; a++ {
-		for b := i; b < a; b++ {
-			if (*s)[a] == (*s)[b] {
-				return true
-			}
-		}
-	}
-	return false
-}
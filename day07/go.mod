@@ -1,3 +0,0 @@
-module day07
-
-go 1.19
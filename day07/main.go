@@ -7,24 +7,30 @@
 //
 // AK, 7 Dec 2022
 
-package main
+package day07
 
 import (
 	"fmt"
-	"io/ioutil"
-	"strconv"
+	"io"
 	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
+func init() {
+	aoc.Register(7, func() aoc.Solver { return &solver{} })
+}
+
 // Directory information is stored in a structure. There is
 // initially one of these for the root directory, and it contains
 // lists of files and subdirectories within.
 type Directory struct {
-	name    string      // name of the directory, just the last part
-	parent  *Directory  // pointer to parent directory
-	files   []File      // list of files
-	subdirs []Directory // list of subdirectories
-	size    int         // including subdirectories (memoization)
+	name    string       // name of the directory, just the last part
+	parent  *Directory   // pointer to parent directory
+	files   []File       // list of files
+	subdirs []*Directory // list of subdirectories
+	size    int          // including subdirectories (memoization)
 }
 
 // Information about one file
@@ -33,45 +39,56 @@ type File struct {
 	size int
 }
 
-// Global variables for Parts 1 and 2
-var Part1Total, Part2Size int
+// The parsed input: the root directory
+type solver struct {
+	root *Directory
+}
 
-func main() {
+// Read the input, and process each line: change directories, and build
+// up recursive lists of files and subdirectories
+func (s *solver) Parse(r io.Reader) error {
 
-	// Read the input file
-	//filename := "sample.txt"
-	filename := "input.txt" // uncomment appropriate file name
-	blob, _ := ioutil.ReadFile(filename)
-	lines := strings.Split(string(blob), "\n")
-	fmt.Println(len(lines), "lines read")
+	// Read the input
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
 
 	// Process each line: change directories, and build up recursive lists of
 	// files and subdirectories
-	root := Directory{name: "/"} // Start by creating one root directory
-	var curdir *Directory        // Pointer to the current directory, set by "cd" command
-	for _, l := range lines {    // Go through each line of input
+	root := &Directory{name: "/"} // Start by creating one root directory
+	var curdir *Directory         // Pointer to the current directory, set by "cd" command
+	for i, l := range lines {     // Go through each line of input
 
 		// Commands start with $: cd to change dir (ignore ls)
 		words := strings.Split(l, " ")
+		if len(words) < 2 {
+			return aocutil.LineErrorf(i+1, "invalid line: %q", l)
+		}
 		if words[0] == "$" {
 			if words[1] == "cd" {
+				if len(words) < 3 {
+					return aocutil.LineErrorf(i+1, "missing directory: %q", l)
+				}
 				if words[2] == "/" { // change to root
-					curdir = &root
+					curdir = root
+				} else if curdir == nil || (words[2] == ".." && curdir.parent == nil) {
+					return aocutil.LineErrorf(i+1, "cannot change directory: %q", l)
 				} else if words[2] == ".." { // up one dir
 					curdir = curdir.parent
 				} else { // change into directory below, create if necessary
 					found := false
-					for i := 0; i < len(curdir.subdirs); i++ {
-						if curdir.subdirs[i].name == words[2] {
-							curdir = &curdir.subdirs[i]
+					for _, sd := range curdir.subdirs {
+						if sd.name == words[2] {
+							curdir = sd
 							found = true
 							break
 						}
 					}
 					if !found { // New directory does not exist, create it
-						newDir := Directory{name: words[2], parent: curdir}
+						newDir := &Directory{name: words[2], parent: curdir}
 						curdir.subdirs = append(curdir.subdirs, newDir)
-						curdir = &curdir.subdirs[len(curdir.subdirs)-1] // not &newDir
+						curdir = newDir
 					}
 				}
 			}
@@ -79,42 +96,69 @@ func main() {
 			// Otherwise add file with its size to current directory (ignore
 			// subdirectory name output)
 		} else if words[0] != "dir" { // ignore subdir name output
-			f := File{name: words[1], size: atoi(words[0])} // create a File object
-			curdir.files = append(curdir.files, f)          // add it to list for current subdir
+			if curdir == nil {
+				return aocutil.LineErrorf(i+1, "file outside any directory: %q", l)
+			}
+			size, err := aocutil.ParseInt(words[0])
+			if err != nil {
+				return aocutil.AtLine(err, i+1, l)
+			}
+			f := File{name: words[1], size: size}  // create a File object
+			curdir.files = append(curdir.files, f) // add it to list for current subdir
 		}
 	}
 
-	// Part 1: get the size of each directory, report sum of those <= 100k
-	used := root.totSize() // calculates sizes of all directories, incl Part 1
-	fmt.Println("Part 1:", Part1Total)
+	// Calculate sizes of all directories
+	root.totSize()
+	s.root = root
+	return nil
+}
+
+// Part 1: get the size of each directory, report sum of those <= 100k
+func (s *solver) Part1() (string, error) {
+	return fmt.Sprint(sumSmall(s.root)), nil
+}
+
+// Part 2: given capacity of 70000000 and used space, find the smallest
+// directory that is big enough to free up the required 30000000 space
+func (s *solver) Part2() (string, error) {
+	unused := 70000000 - s.root.size // current free space
+	freeUp := 30000000 - unused      // amount we need to free up
+	return fmt.Sprint(smallest(s.root, freeUp, 0)), nil
+}
 
-	// Part 2: given capacity of 70000000 and used space, find the smallest
-	// directory that is big enough to free up the required 30000000 space
-	unused := 70000000 - used   // current free space
-	freeUp := 30000000 - unused // amount we need to free up
-	part2(&root, freeUp)        // finds smallest directory that exceeds freeUp
-	fmt.Println("Part 2:", Part2Size)
+// For Part 1, add up sizes of all directories <= 100k, including subdirs
+// Assumes that totSize() has already been run on root.
+func sumSmall(d *Directory) int {
+	tot := 0
+	if d.size <= 100000 {
+		tot += d.size
+	}
+	for _, subdir := range d.subdirs {
+		tot += sumSmall(subdir)
+	}
+	return tot
 }
 
 // For Part 2, walk through directories, find smallest directory that
-// has size >= freeUp
-func part2(d *Directory, freeUp int) {
+// has size >= freeUp, given the best found so far (zero if none)
+func smallest(d *Directory, freeUp, best int) int {
 
 	// Check this directory: does it exceed the space required, and is it
-	// smaller than any solution found so far? If so, set the global
-	// variable Part2Size to the directory size.
+	// smaller than any solution found so far?
 	// Note we don't need to check to exclude the root directory, since its
 	// size will be too big anyway.
 	// Assumes that totSize() has already been run on root, so that .size
-	// is calculated for every directory (done in Part 1).
-	if d.size >= freeUp && (Part2Size == 0 || d.size < Part2Size) {
-		Part2Size = d.size
+	// is calculated for every directory.
+	if d.size >= freeUp && (best == 0 || d.size < best) {
+		best = d.size
 	}
 
 	// Check all subdirectories
 	for _, subdir := range d.subdirs {
-		part2(&subdir, freeUp)
+		best = smallest(subdir, freeUp, best)
 	}
+	return best
 }
 
 // Get size of a directory, including subdirs
@@ -132,26 +176,11 @@ func (d *Directory) totSize() int {
 	}
 
 	// Sum up subdirectories
-	for i := 0; i < len(d.subdirs); i++ {
-		tot += d.subdirs[i].totSize()
-	}
-
-	// For part 1, add up sizes <= 100k
-	if tot <= 100000 {
-		Part1Total += tot
+	for _, sd := range d.subdirs {
+		tot += sd.totSize()
 	}
 
 	// Return size of this directory, including its subdirs
 	d.size = tot // remember size, so don't have to recalculate (memoization)
 	return tot
 }
-
-// Parse an integer, show message and return -1 if error
-func atoi(s string) int {
-	n, err := strconv.ParseInt(s, 10, 64)
-	if err != nil {
-		fmt.Println("Could not parse integer:", s)
-		n = -1
-	}
-	return int(n)
-}
@@ -1,3 +0,0 @@
-module day08
-
-go 1.19
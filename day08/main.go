@@ -7,35 +7,64 @@
 //
 // AK, 8 Dec 2022
 
-package main
+package day08
 
 import (
 	"fmt"
+	"io"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/grid"
 )
 
-func main() {
+func init() {
+	aoc.Register(8, func() aoc.Solver { return &solver{} })
+}
+
+// The parsed input: the heights of the trees
+type solver struct {
+	forest *grid.Grid[byte]
+}
+
+// Read the input into a grid of tree heights, all lines must be the same
+// length
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	s.forest, err = readForest(lines)
+	return err
+}
 
-	// Read the input file
-	//lines := readLines("sample.txt")
-	lines := readLines("input.txt")
-	fmt.Println(len(lines), "lines read")
+// Make a grid of tree heights from lines of digits
+func readForest(lines []string) (*grid.Grid[byte], error) {
+	return grid.FromLinesFunc(lines, func(c byte) (byte, error) {
+		if c < '0' || c > '9' {
+			return 0, aocutil.ErrInt
+		}
+		return c - '0', nil
+	})
+}
 
-	// Part 1: count how many trees are "visible"
-	fmt.Println("Part 1 (s/b 21 or 1763):", part1(lines))
+// Part 1: count how many trees are "visible" (s/b 21 or 1763)
+func (s *solver) Part1() (string, error) {
+	return fmt.Sprint(part1(s.forest)), nil
+}
 
-	// Part 2: maximum scenic score
-	// 560 and 14400 both too low
-	fmt.Println("Part 2 (s/b 8 or 671160):", part2(lines))
+// Part 2: maximum scenic score (s/b 8 or 671160)
+// 560 and 14400 both too low
+func (s *solver) Part2() (string, error) {
+	return fmt.Sprint(part2(s.forest)), nil
 }
 
 // Part 1: how many trees are visible?
-func part1(lines []string) int {
-	nr := len(lines)
-	nc := len(lines[0])
+func part1(forest *grid.Grid[byte]) int {
 	nvis := 0
-	for r := 0; r < nr; r++ {
-		for c := 0; c < nc; c++ {
-			if isVisible(r, c, lines) {
+	for r := 0; r < forest.Height(); r++ {
+		for c := 0; c < forest.Width(); c++ {
+			if isVisible(r, c, forest) {
 				nvis++
 			}
 		}
@@ -44,11 +73,11 @@ func part1(lines []string) int {
 }
 
 // For Part 1, is tree in the given position "visible" from any direction?
-func isVisible(r, c int, forest []string) bool {
+func isVisible(r, c int, forest *grid.Grid[byte]) bool {
 
 	// Trees on the edges are always visible
-	nr := len(forest)
-	nc := len(forest[0])
+	nr := forest.Height()
+	nc := forest.Width()
 	if r == 0 || r == nr-1 || c == 0 || c == nc-1 {
 		return true
 	}
@@ -56,48 +85,31 @@ func isVisible(r, c int, forest []string) bool {
 	// Otherwise, look up & down left & right, to see
 	// if there is an "opening" in any direction, i.e.,
 	// all trees in that direction are lower than this one
-	x := forest[r][c] // this "tree"
-
-	visLeft := true
-	for i := 0; i < c; i++ { // left
-		if forest[r][i] >= x {
-			visLeft = false
-		}
-	}
-
-	visRight := true
-	for i := c + 1; i < nc; i++ { // right
-		if forest[r][i] >= x {
-			visRight = false
-		}
-	}
-
-	visAbove := true
-	for i := 0; i < r; i++ {
-		if forest[i][c] >= x { // above
-			visAbove = false
-		}
-	}
+	x := forest.At(c, r) // this "tree"
+	row := forest.Row(r)
+	col := forest.Col(c)
+	return allLower(row[:c], x) || // left
+		allLower(row[c+1:], x) || // right
+		allLower(col[:r], x) || // above
+		allLower(col[r+1:], x) // below
+}
 
-	visBelow := true
-	for i := r + 1; i < nr; i++ {
-		if forest[i][c] >= x { // below
-			visBelow = false
+// Are all the trees in a line lower than a given height?
+func allLower(trees []byte, x byte) bool {
+	for _, t := range trees {
+		if t >= x {
+			return false
 		}
 	}
-
-	// A tree is visible if not blocked from all directions
-	return visLeft || visRight || visAbove || visBelow
+	return true
 }
 
 // Part 2: maximum "score" of tree visibility
-func part2(lines []string) int {
+func part2(forest *grid.Grid[byte]) int {
 	score := 0
-	nr := len(lines)
-	nc := len(lines[0])
-	for r := 0; r < nr; r++ {
-		for c := 0; c < nc; c++ {
-			ss := scenicScore(r, c, lines)
+	for r := 0; r < forest.Height(); r++ {
+		for c := 0; c < forest.Width(); c++ {
+			ss := scenicScore(r, c, forest)
 			if ss > score {
 				score = ss
 			}
@@ -106,47 +118,38 @@ func part2(lines []string) int {
 	return score
 }
 
-// For Part 1, is tree in the given position "visible"
-func scenicScore(r, c int, forest []string) int {
+// For Part 2, the "scenic score" of the tree in the given position
+func scenicScore(r, c int, forest *grid.Grid[byte]) int {
 
 	// If on the edge, score is zero
+	nr := forest.Height()
+	nc := forest.Width()
 	if r == 0 || r == nr-1 || c == 0 || c == nc-1 {
 		return 0
 	}
 
 	// Count how many trees are visible in each direction, by extracting the
 	// trees from the current tree in each direction, then checking that sequence
-	nr := len(forest)
-	nc := len(forest[0])
-
-	seq := []byte{}
-	for i := c; i >= 0; i-- { // left
-		seq = append(seq, forest[r][i])
-	}
-	visLeft := nVisible(seq)
-
-	seq = []byte{}
-	for i := c; i < nc; i++ { // right
-		seq = append(seq, forest[r][i])
-	}
-	visRight := nVisible(seq)
-
-	seq = []byte{}
-	for i := r; i >= 0; i-- { // up
-		seq = append(seq, forest[i][c])
-	}
-	visUp := nVisible(seq)
-
-	seq = []byte{}
-	for i := r; i < nr; i++ { // down
-		seq = append(seq, forest[i][c])
-	}
-	visDown := nVisible(seq)
+	row := forest.Row(r)
+	col := forest.Col(c)
+	visLeft := nVisible(reversed(row[:c+1]))
+	visRight := nVisible(row[c:])
+	visUp := nVisible(reversed(col[:r+1]))
+	visDown := nVisible(col[r:])
 
 	// Return product to get score
 	return visLeft * visRight * visUp * visDown
 }
 
+// A reversed copy of a line of trees
+func reversed(trees []byte) []byte {
+	rev := make([]byte, len(trees))
+	for i, t := range trees {
+		rev[len(trees)-1-i] = t
+	}
+	return rev
+}
+
 // Count the number of trees visible from the first tree in given sequence,
 // i.e., not higher than the starting tree
 func nVisible(trees []byte) int {
@@ -1,3 +0,0 @@
-module day09
-
-go 1.19
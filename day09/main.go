@@ -7,45 +7,77 @@
 //
 // AK, 9 Dec 2022
 
-package main
+package day09
 
 import (
 	"fmt"
+	"io"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
+func init() {
+	aoc.Register(9, func() aoc.Solver { return &solver{} })
+}
+
 // A position in 2D space
 type Position struct {
 	x, y int
 }
 
-func main() {
+// An instruction to move the head n steps in a direction
+type Move struct {
+	dir byte // R/U/L/D
+	n   int  // steps to move
+}
 
-	// Read the input file, a list of "Dir n" instructions
-	//lines := readLines("sample2.txt")  // use sample2.txt for part 2
-	lines := readLines("input.txt")
-	fmt.Println(len(lines), "lines read")
+// The parsed input: a list of "Dir n" instructions
+type solver struct {
+	moves []Move
+}
 
-	// Part 1: number of tail positions visited, with just 2 knots
-	fmt.Println("Part 1 (s/b 13, 5735):", simulate(lines, 2))
+// Read the input, a list of "Dir n" instructions
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	s.moves = nil
+	for i, l := range lines {
+		if len(l) < 3 || !aocutil.In(l[0], []byte("RULD")) || l[1] != ' ' {
+			return aocutil.LineErrorf(i+1, "invalid instruction: %q", l)
+		}
+		n, err := aocutil.ParseInt(l[2:])
+		if err != nil {
+			return aocutil.AtLine(err, i+1, l)
+		}
+		s.moves = append(s.moves, Move{l[0], n})
+	}
+	return nil
+}
 
-	// Part 2: same, with 10 knots
-	fmt.Println("Part 2 (s/b 36, 2478):", simulate(lines, 10))
+// Part 1: number of tail positions visited, with just 2 knots
+// (s/b 13, 5735)
+func (s *solver) Part1() (string, error) {
+	return fmt.Sprint(simulate(s.moves, 2)), nil
+}
 
+// Part 2: same, with 10 knots (s/b 36 for sample2.txt, 2478)
+func (s *solver) Part2() (string, error) {
+	return fmt.Sprint(simulate(s.moves, 10)), nil
 }
 
 // Simulate movement of "knots" along a rope, return the number of positions
 // visited by the last "knot"
-func simulate(lines []string, knots int) int {
+func simulate(moves []Move, knots int) int {
 
 	// Process each line
 	tVisited := map[Position]int{}              // places the tail has visited
 	positions := make([]Position, knots, knots) // current positions of 10 knots
 	tVisited[Position{0, 0}] = 1                // tail has already visited 0,0
-	for _, l := range lines {
-
-		// Parse instruction
-		dir := l[0]      // R/U/L/D
-		n := atoi(l[2:]) // steps to move
+	for _, m := range moves {
+		dir, n := m.dir, m.n
 
 		// Do each step of the instruction
 		for i := 0; i < n; i++ {
@@ -70,20 +102,20 @@ func simulate(lines []string, knots int) int {
 				t := &positions[k]  // this one
 
 				// If knot is "touching" the previous one, no need to adjust
-				if abs(h.x-t.x) <= 1 && abs(h.y-t.y) <= 1 {
+				if aocutil.Abs(h.x-t.x) <= 1 && aocutil.Abs(h.y-t.y) <= 1 {
 					continue
 				}
 
 				// If the knot is two steps up, down, left, or right from the
 				// previous one, move previous one step in that direction
-				if t.y == h.y && abs(t.x-h.x) == 2 { // left/right
-					t.x += ifElse(t.x > h.x, -1, 1)
-				} else if t.x == h.x && abs(t.y-h.y) == 2 { // up/down
-					t.y += ifElse(t.y > h.y, -1, 1)
+				if t.y == h.y && aocutil.Abs(t.x-h.x) == 2 { // left/right
+					t.x += aocutil.IfElse(t.x > h.x, -1, 1)
+				} else if t.x == h.x && aocutil.Abs(t.y-h.y) == 2 { // up/down
+					t.y += aocutil.IfElse(t.y > h.y, -1, 1)
 				} else {
 					// Otherwise, move tail one step diagonally to keep up
-					t.y += ifElse(t.y > h.y, -1, 1)
-					t.x += ifElse(t.x > h.x, -1, 1)
+					t.y += aocutil.IfElse(t.y > h.y, -1, 1)
+					t.x += aocutil.IfElse(t.x > h.x, -1, 1)
 				}
 			}
 
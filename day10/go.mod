@@ -1,3 +0,0 @@
-module day10
-
-go 1.19
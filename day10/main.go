@@ -8,42 +8,89 @@
 //
 // AK, 10 Dec 2022
 
-package main
+package day10
 
 import (
 	"fmt"
+	"io"
 	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
-func main() {
+func init() {
+	aoc.Register(10, func() aoc.Solver { return &solver{} })
+}
+
+// Number of cycles that either part looks at (one for each pixel on
+// the 40x6 screen), the rest of the input is checked but not kept
+const maxCycles = 6 * 40
+
+// The parsed input: value of the register *during* each of the first
+// maxCycles cycles, and the total number of cycles
+type solver struct {
+	acc     []int
+	ncycles int
+}
 
-	// Part 1: process each line and simulate the add/noop instructions,
-	// building up accumulator over each cycle (addx is two cycles)
-	lines := readLines("input.txt") // for part 1: "sample.txt"
-	X := 1                          // the register starts at 1
-	acc := []int{}                  // value of the register *during* each cycle
-	for _, l := range lines {
+// Read the input one line at a time: process each line and simulate the
+// add/noop instructions, building up accumulator over each cycle (addx is
+// two cycles)
+func (s *solver) Parse(r io.Reader) error {
+	in := aocutil.NewReader(r)
+	X := 1         // the register starts at 1
+	acc := []int{} // value of the register *during* each cycle
+	ncycles := 0   // number of cycles so far
+	for in.Next() {
+		l := in.Text()
+		if len(l) == 0 {
+			continue
+		}
 		words := strings.Split(l, " ")
-		if words[0] == "noop" {
+		if words[0] == "noop" && len(words) == 1 {
 			acc = append(acc, X) // no change, one cycle
-		} else { // expect "addx <n>"
+			ncycles++
+		} else if words[0] == "addx" && len(words) == 2 {
+			n, err := aocutil.ParseInt(words[1])
+			if err != nil {
+				return in.AtLine(err, 0)
+			}
 			acc = append(acc, X, X) // takes two cycles, still at old value
-			X += atoi(words[1])     // the new value
+			ncycles += 2
+			X += n // the new value
+		} else {
+			return in.Errorf("invalid instruction: %q", l)
+		}
+		if len(acc) > maxCycles {
+			acc = acc[:maxCycles]
 		}
 	}
+	s.acc, s.ncycles = acc, ncycles
+	return in.Err()
+}
 
-	// Part 1: sumproduct of certain cycles and register values
+// Part 1: sumproduct of certain cycles and register values
+// (s/b 13140 for sample)
+func (s *solver) Part1() (string, error) {
+	if s.ncycles < 220 {
+		return "", fmt.Errorf("only %d cycles", s.ncycles)
+	}
 	part1 := 0
 	for _, i := range []int{20, 60, 100, 140, 180, 220} {
-		part1 += i * acc[i-1]
+		part1 += i * s.acc[i-1]
 	}
-	fmt.Println("Part 1 (s/b 13140):", part1)
+	return fmt.Sprint(part1), nil
+}
 
-	// For part 2, draw pixels on a 40x6 virtual screen, one pixel per cycle
-	screen := make([]int, 6*40, 6*40)
-	var h int                       // current horizontal position
-	for t := 0; t < len(acc); t++ { // each cycle
-		if abs(acc[t]-h) <= 1 { // if acc close to horizontal position,
+// For part 2, draw pixels on a 40x6 virtual screen, one pixel per cycle,
+// and return the screen (shows EZFPRAKL)
+func (s *solver) Part2() (string, error) {
+	acc := s.acc
+	screen := make([]int, maxCycles)
+	var h int                                          // current horizontal position
+	for t := 0; t < len(acc) && t < len(screen); t++ { // each cycle
+		if aocutil.Abs(acc[t]-h) <= 1 { // if acc close to horizontal position,
 			screen[t] = 1 // turn on pixel
 		}
 		h++         // next horizontal position on screen
@@ -52,12 +99,13 @@ func main() {
 		}
 	}
 
-	// Print the final screen, shows EZFPRAKL
-	fmt.Println("Part 2:")
+	// Draw the final screen
+	var sb strings.Builder
 	for p := 0; p < len(screen); p++ { // each pixel
 		if p > 0 && p%40 == 0 {
-			fmt.Print("\n") // start new row
+			sb.WriteString("\n") // start new row
 		}
-		fmt.Print(ifElse(screen[p] == 0, " ", "X"))
+		sb.WriteString(aocutil.IfElse(screen[p] == 0, " ", "X"))
 	}
+	return sb.String(), nil
 }
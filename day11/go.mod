@@ -1,3 +0,0 @@
-module day11
-
-go 1.19
@@ -12,42 +12,117 @@
 //
 // AK, 11 Dec 2022
 
-package main
+package day11
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
 	"sort"
-	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/num"
 )
 
+func init() {
+	aoc.Register(11, func() aoc.Solver { return &solver{} })
+}
+
 // State of a monkey
 type Monkey struct {
-	id              int      // number of this monkey (0...)
-	items           []int64  // worry levels of items it holds
-	operation       []string // old*old, old+n, old*n
-	test            int64    // divisible by this number
-	ifTrue, ifFalse int      // next monkey to throw to
-	inspections     int      // number of inspections made
+	id              int     // number of this monkey (0...)
+	items           []int64 // worry levels of items it holds
+	add             bool    // operation is old+..., rather than old*...
+	operand         int64   // number to add or multiply by
+	old             bool    // operand is old itself, e.g., old*old
+	test            int64   // divisible by this number
+	ifTrue, ifFalse int     // next monkey to throw to
+}
+
+// The parsed input: the starting state of the monkeys, and the kind of
+// numbers to use for the worry levels (see package num)
+type solver struct {
+	monkeys []Monkey
+	num     string
 }
 
-// We need this number to prevent the weights from getting too big
-var magic int64
+// Options: num=int64 (checked, the default), big or rat
+func (s *solver) SetOption(name, value string) error {
+	if name != "num" {
+		return fmt.Errorf("unknown option %q", name)
+	}
+	s.num = value
+	return num.CheckKind(value)
+}
 
-func main() {
+// Read and parse "monkeys" from the input
+func (s *solver) Parse(r io.Reader) error {
+	var err error
+	s.monkeys, err = readMonkeys(r)
+	return err
+}
 
-	// Set this to true for Part 1, false for Part 2
-	part1 := false
+// Part 1: 20 rounds, worry level divided by 3 after each inspection
+// (on sample, s/b 10605)
+func (s *solver) Part1() (string, error) {
+	return s.run(true)
+}
 
-	// Read and parse "monkeys" from input file, show starting state
-	monkeys := readMonkeys("sample.txt")
-	//monkeys := readMonkeys("input.txt")
-	fmt.Println("Before simulation:")
+// Part 2: 10000 rounds, without dividing worry levels
+// (on sample, s/b 2713310158)
+func (s *solver) Part2() (string, error) {
+	return s.run(false)
+}
+
+// Run the simulation with the kind of numbers chosen
+func (s *solver) run(part1 bool) (string, error) {
+	var ans int
+	var err error
+	switch s.num {
+	case num.KindBig:
+		ans, err = simulate[*big.Int](s.monkeys, num.BigInt{}, part1)
+	case num.KindRat:
+		ans, err = simulate[*big.Rat](s.monkeys, num.BigRat{}, part1)
+	default:
+		ans, err = simulate[int64](s.monkeys, num.Int64{}, part1)
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(ans), nil
+}
+
+// Run the simulation, part1 is true for Part 1, false for Part 2, and
+// return the product of the two highest inspection counts. The worry
+// levels are numbers of type T, so an overflow is an error rather than
+// a wrong answer.
+func simulate[T any](monkeys []Monkey, a num.Arith[T], part1 bool) (int, error) {
+
+	// Work on copies of the items, with the worry levels as T
+	items := make([][]T, len(monkeys))
+	for i, m := range monkeys {
+		for _, wl := range m.items {
+			items[i] = append(items[i], a.Int(wl))
+		}
+	}
+	inspections := make([]int, len(monkeys)) // number of inspections made
+
+	// For part 2, we need to keep the worry level from getting too big, by
+	// taking the modulo of it and our "magic" number, which is all the
+	// test divisors multiplied together (thanks to my son Alexander for
+	// helping me figure this out!)
+	magic := a.Int(1)
 	for _, m := range monkeys {
-		fmt.Println(m)
+		var err error
+		if magic, err = a.Mul(magic, a.Int(m.test)); err != nil {
+			return 0, fmt.Errorf("product of the tests: %w", err)
+		}
 	}
 
 	// Do the simulation for 20 or 10k rounds
-	niters := ifElse(part1, 20, 10000)
+	niters := aocutil.IfElse(part1, 20, 10000)
 	for round := 1; round <= niters; round++ {
 
 		// Do each monkey
@@ -55,110 +130,124 @@ func main() {
 
 			// Process each item the monkey holds
 			m := &monkeys[mi]
-			for len(m.items) > 0 {
+			for len(items[mi]) > 0 {
 
 				// Apply operation to the worry level
-				m.inspections++
-				wl := m.items[0] // current worry level
-				wl = applyOperation(wl, m.operation)
+				inspections[mi]++
+				wl, err := applyOperation(a, items[mi][0], m)
 
 				// Now integer-divide by 3 for Part 1
-				if part1 {
-					wl /= 3
+				if part1 && err == nil {
+					wl, err = a.Quo(wl, a.Int(3))
 				}
 
-				// For part 2, we also need to adjust the worry level from
-				// getting too big, by taking the modulo of it and our "magic"
-				// number, which is all the test divisors multiplied together
-				// (thanks to my son Alexander for helping me figure this out!)
-				wl = wl % magic
+				// Adjust the worry level by the magic number
+				if err == nil {
+					wl, err = a.Rem(wl, magic)
+				}
 
 				// Apply test to determine who to throw to
-				dest := m.ifTrue    // assume divisible by test
-				if wl%m.test != 0 { // route to other monkey if not
+				var rem T
+				if err == nil {
+					rem, err = a.Rem(wl, a.Int(m.test))
+				}
+				if err != nil {
+					return 0, fmt.Errorf("round %d, monkey %d: %w", round, m.id, err)
+				}
+				dest := m.ifTrue               // assume divisible by test
+				if a.Cmp(rem, a.Int(0)) != 0 { // route to other monkey if not
 					dest = m.ifFalse
 				}
 
 				// Remove the worry level from this monkey's list, and
 				// add it to the destination monkey's list
-				m.items = m.items[1:]
-				monkeys[dest].items = append(monkeys[dest].items, wl)
+				items[mi] = items[mi][1:]
+				items[dest] = append(items[dest], wl)
 			}
 		}
 	}
 
 	// Get the two highest inspections, answer is the product
-	fmt.Println("\nAfter simulation:")
-	ii := []int{}
-	for _, m := range monkeys {
-		fmt.Println(m)
-		ii = append(ii, m.inspections)
-	}
-	sort.Ints(ii)
-	fmt.Println("\nFinal number of inspections (s/b 1938, 47830, 52013, 52166):", ii)
-	fmt.Println("Answer (on sample, s/b 10605 for Part 1, 2713310158 for Part 2):", ii[len(ii)-1]*ii[len(ii)-2])
+	// (final number of inspections on sample s/b 1938, 47830, 52013, 52166)
+	sort.Ints(inspections)
+	return inspections[len(inspections)-1] * inspections[len(inspections)-2], nil
 }
 
-// Apply an operation to a number: old*old, old+n, old*n
-func applyOperation(wl int64, op []string) int64 {
-	assert(op[0] == "old" && (op[1] == "+" || op[1] == "*"), "Invalid operation")
-	if op[1] == "+" {
-		return wl + atoi64(op[2])
-	} else if op[2] == "old" {
-		return wl * wl // this overflows in Part 2 without adjustment!
-	} else {
-		return wl * atoi64(op[2])
+// Apply a monkey's operation to a number: old+old, old+n, old*old, old*n
+func applyOperation[T any](a num.Arith[T], wl T, m *Monkey) (T, error) {
+	operand := aocutil.IfElse(m.old, wl, a.Int(m.operand))
+	if m.add {
+		return a.Add(wl, operand)
 	}
+	return a.Mul(wl, operand) // old*old overflows in Part 2 without adjustment!
 }
 
-// Read and parse "monkeys" from input file
-func readMonkeys(filename string) []Monkey {
+// Format of the lines for each monkey, in order
+var monkeyLines = []*aocutil.Pattern{
+	aocutil.MustPattern("Monkey {int}:"),
+	aocutil.MustPattern("Starting items: {ints}"),
+	aocutil.MustPattern("Operation: new = old {word} {word}"),
+	aocutil.MustPattern("Test: divisible by {int}"),
+	aocutil.MustPattern("If true: throw to monkey {int}"),
+	aocutil.MustPattern("If false: throw to monkey {int}"),
+}
+
+// Read and parse "monkeys" from input
+func readMonkeys(r io.Reader) ([]Monkey, error) {
 
-	// Create empty list of monkeys, and initial monkey
+	// Create empty list of monkeys
 	monkeys := []Monkey{}
-	m := Monkey{id: 0}
-
-	// Initialize global variable of all the test divisors
-	// multiplied together, for part 2
-	magic = 1
-
-	// Process each line of input file
-	lines := readLines(filename)
-	for _, l := range lines {
-
-		// Blank line starts a new "monkey"
-		l = strings.TrimSpace(l)
-		if len(l) == 0 {
-			monkeys = append(monkeys, m)
-			m = Monkey{id: len(monkeys)} // add current monkey to list
-			continue
+
+	// Process each record of the input file, i.e., each "monkey" (blank
+	// lines separate the monkeys)
+	in := aocutil.NewReader(r)
+	for in.NextRecord() {
+		rec := in.Record()
+		if len(rec) != len(monkeyLines) {
+			return nil, in.Errorf("monkey has %d lines, not %d", len(rec), len(monkeyLines))
 		}
 
-		// Otherwise fill in fields about the current monkey
-		words := strings.Split(l, " ")
-		if words[0] == "Starting" { // list of numbers
-			for i := 2; i < len(words); i++ {
-				n := words[i]
-				if n[len(n)-1] == ',' {
-					n = n[:len(n)-1]
-				}
-				m.items = append(m.items, atoi64(n))
+		// Fill in fields about the current monkey, one line at a time
+		m := Monkey{id: len(monkeys)}
+		var id int
+		var items []int
+		var op, operand string
+		for i, dst := range [][]any{{&id}, {&items}, {&op, &operand}, {&m.test}, {&m.ifTrue}, {&m.ifFalse}} {
+			if err := monkeyLines[i].Match(rec[i], dst...); err != nil {
+				return nil, in.AtLine(err, i)
 			}
-		} else if words[0] == "Operation:" {
-			m.operation = words[3:]
-		} else if words[0] == "Test:" { // e.g., "divisible by 13"
-			m.test = atoi64(words[3])
-			magic *= m.test
-		} else if words[1] == "true:" { // e.g., "throw to monkey 3"
-			m.ifTrue = atoi(words[5])
-		} else if words[1] == "false:" {
-			m.ifFalse = atoi(words[5])
-		} else if !strings.HasPrefix(l, "Monkey ") {
-			panic("Invalid line: " + l)
 		}
+		if id != m.id {
+			return nil, in.Errorf("expected monkey %d, found monkey %d", m.id, id)
+		}
+		for _, n := range items {
+			m.items = append(m.items, int64(n))
+		}
+		if op != "+" && op != "*" {
+			return nil, in.AtLine(&aocutil.ParseError{Token: op, Err: errors.New("invalid operation")}, 2)
+		}
+		m.add, m.old = op == "+", operand == "old"
+		if !m.old {
+			var err error
+			if m.operand, err = aocutil.ParseInt64(operand); err != nil {
+				return nil, in.AtLine(err, 2)
+			}
+		}
+		monkeys = append(monkeys, m) // add current monkey to list
+	}
+	if err := in.Err(); err != nil {
+		return nil, err
 	}
 
-	// Add the last monkey and return list
-	monkeys = append(monkeys, m)
-	return monkeys
+	// Check the list of monkeys and return it
+	if len(monkeys) < 2 {
+		return nil, fmt.Errorf("need at least two monkeys")
+	}
+	for _, m := range monkeys {
+		if m.test <= 0 || m.ifTrue < 0 || m.ifTrue >= len(monkeys) ||
+			m.ifFalse < 0 || m.ifFalse >= len(monkeys) {
+			return nil, fmt.Errorf("invalid monkey %d", m.id)
+		}
+	}
+	return monkeys, nil
 }
@@ -6,100 +6,112 @@
 //
 // AK, 12/12/2022
 
-package main
+package day12
 
 import (
 	"fmt"
+	"io"
 
-	// You need to install this: go get github.com/yourbasic/graph
-	"github.com/yourbasic/graph"
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/graph"
+	"adventofcode2022/grid"
 )
 
-// Main execution: run parts 1 and 2
-func main() {
+func init() {
+	aoc.Register(12, func() aoc.Solver { return &solver{} })
+}
 
-	// Read file into a pseudo-matrix of byte rows
-	// fname = "sample.txt"  // uncomment one file name
-	fname := "input.txt"
-	m := [][]byte{}
-	for _, l := range readLines(fname) {
-		m = append(m, []byte(l))
-	}
+// The parsed input: the terrain (with S and E replaced by their altitudes),
+// the graph of feasible steps (labelled with the positions), and the node
+// numbers of S and E
+type solver struct {
+	m    *grid.Grid[byte]
+	g    *graph.Graph[grid.Point]
+	S, E int
+}
 
-	// Find S and E first, adjust their altitudes (otherwise won't work)
-	var S, E int
-	nr := len(m)    // number of rows
-	nc := len(m[0]) //number of columns
-	for ri := 0; ri < nr; ri++ {
-		for ci := 0; ci < nc; ci++ {
-			if m[ri][ci] == 'S' {
-				S = ri*nc + ci  // this is the node number of S
-				m[ri][ci] = 'a' // replace 'S' with 'a'
-			} else if m[ri][ci] == 'E' {
-				E = ri*nc + ci  // node number for E
-				m[ri][ci] = 'z' // change 'E' to 'z' per problem text
-			}
+// Read the input and build the graph of feasible steps
+func (s *solver) Parse(r io.Reader) error {
+
+	// Read file into a grid of letters
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	m, err := grid.FromLinesFunc(lines, func(c byte) (byte, error) {
+		if (c < 'a' || c > 'z') && c != 'S' && c != 'E' {
+			return 0, aocutil.ErrFormat
 		}
+		return c, nil
+	})
+	if err != nil {
+		return err
 	}
-	fmt.Println("S =", S, ", E =", E) // at least one should be non-zero
 
-	// Build the graph: from each cell, add feasible steps to the right
-	// and/or down, also in reverse direction if that is also feasible.
-	// Here, feasible means "vertical climb" at most 1 (i.e., next cell is at
-	// most one letter higher). Always use a cost (e.g., 1), otherwise
-	// shortest path will be randomized (because there is no cost).
-	g := graph.New(nr * nc) // create graph with enough capacity for all nodes
-	for ri := 0; ri < nr; ri++ {
-		for ci := 0; ci < nc; ci++ {
-
-			// This starting node
-			thisNode := ri*nc + ci       // the node number
-			thisLetter := int(m[ri][ci]) // letter in this cell
-			assert(thisLetter >= 'a' && thisLetter <= 'z', "Bad letter")
-
-			// Go right (and left from there) if possible
-			if ci < nc-1 { // all but last column
-				nextNode := ri*nc + (ci + 1)    // node to the right
-				nextLetter := int(m[ri][ci+1])  // letter in that cell
-				if nextLetter-thisLetter <= 1 { // if going up at most 1,
-					g.AddCost(thisNode, nextNode, 1) // can go right
-				}
-				if thisLetter-nextLetter <= 1 { // same for go left
-					g.AddCost(nextNode, thisNode, 1)
-				}
-			}
+	// Find S and E first, adjust their altitudes (otherwise won't work)
+	sp, okS := grid.Find(m, 'S')
+	ep, okE := grid.Find(m, 'E')
+	if !okS || !okE {
+		return fmt.Errorf("S or E not found")
+	}
+	m.Set(sp.X, sp.Y, 'a') // replace 'S' with 'a'
+	m.Set(ep.X, ep.Y, 'z') // change 'E' to 'z' per problem text
 
-			// Go down/up if possible
-			if ri < nr-1 {
-				nextNode := (ri+1)*nc + ci
-				nextLetter := int(m[ri+1][ci])
-				if nextLetter-thisLetter <= 1 { // go down
-					g.AddCost(thisNode, nextNode, 1)
-				}
-				if thisLetter-nextLetter <= 1 { // up
-					g.AddCost(nextNode, thisNode, 1)
+	// Build the graph: one node per cell, then from each cell add feasible
+	// steps to each neighbour. Here, feasible means "vertical climb" at most
+	// 1 (i.e., next cell is at most one letter higher).
+	g := graph.New[grid.Point]()
+	for ri := 0; ri < m.Height(); ri++ {
+		for ci := 0; ci < m.Width(); ci++ {
+			g.AddNode(grid.Point{X: ci, Y: ri})
+		}
+	}
+	for ri := 0; ri < m.Height(); ri++ {
+		for ci := 0; ci < m.Width(); ci++ {
+			thisNode, _ := g.Node(grid.Point{X: ci, Y: ri}) // the node number
+			thisLetter := int(m.At(ci, ri))                 // letter in this cell
+			for _, p := range m.Neighbours4(ci, ri) {
+				nextLetter := int(m.At(p.X, p.Y)) // letter in that cell
+				if nextLetter-thisLetter <= 1 {   // if going up at most 1,
+					nextNode, _ := g.Node(p)
+					g.AddEdge(thisNode, nextNode, 1) // can go there
 				}
 			}
 		}
 	}
 
-	// Part 1: calculate shortest feasible path from S to E
-	_, dist := graph.ShortestPath(g, S, E)
-	fmt.Println("Part 1 (s/b 31, 490):", dist)
+	s.m, s.g = m, g
+	s.S, _ = g.Node(sp) // node numbers of S and E
+	s.E, _ = g.Node(ep)
+	return nil
+}
 
-	// Part 2: find the shortest feasible path from any 'a' cell to E
-	// (note that ShortestPath returns -1 if no path found)
-	var shortest int64
-	for ri := 0; ri < nr; ri++ {
-		for ci := 0; ci < nc; ci++ {
-			if m[ri][ci] == 'a' {
-				thisNode := ri*nc + ci
-				_, dist := graph.ShortestPath(g, thisNode, E)
-				if dist > 0 && (shortest == 0 || dist < shortest) {
-					shortest = dist
-				}
-			}
-		}
+// Part 1: calculate shortest feasible path from S to E, using A* with
+// the number of steps to E ignoring altitude as the estimate (s/b 31, 490)
+func (s *solver) Part1() (string, error) {
+	e := s.g.Label(s.E)
+	_, dist := s.g.AStar(s.S, s.E, func(n int) int {
+		p := s.g.Label(n)
+		return aocutil.Abs(p.X-e.X) + aocutil.Abs(p.Y-e.Y)
+	})
+	if dist == graph.Unreachable {
+		return "", fmt.Errorf("no path from S to E")
+	}
+	return fmt.Sprint(dist), nil
+}
+
+// Part 2: find the shortest feasible path from any 'a' cell to E, with
+// one search starting from all of them at once (s/b 29, 488)
+func (s *solver) Part2() (string, error) {
+	starts := []int{}
+	for _, p := range grid.FindAll(s.m, 'a') {
+		n, _ := s.g.Node(p)
+		starts = append(starts, n)
+	}
+	dist := s.g.BFS(starts...).Dist[s.E]
+	if dist == graph.Unreachable {
+		return "", fmt.Errorf("no path from any 'a' to E")
 	}
-	fmt.Println("Part 2 (s/b 29, 488):", shortest)
+	return fmt.Sprint(dist), nil
 }
@@ -0,0 +1,98 @@
+// Advent of Code 2022, Day 13
+//
+// Given pairs of nested lists of numbers, count up how many are in the
+// right order according to an arcane comparison function (part 1), then
+// combine all the pair elements into one big list, add a couple of marker
+// elements, and sort the list according to the comparison function. For
+// Part 2, report the product of the indices of the two marker elements.
+//
+// Ported from Python (day13.py), with the packets in packet.go.
+//
+// AK, 13 Dec 2022
+
+package day13
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+)
+
+func init() {
+	aoc.Register(13, func() aoc.Solver { return &solver{} })
+}
+
+// The parsed input: the pairs of packets
+type solver struct {
+	pairs [][2]Packet
+}
+
+// Read the input: pairs of packets, one per line, with a blank line
+// after each pair
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	s.pairs = nil
+	var pair []Packet
+	for i, l := range lines {
+		if l == "" {
+			if len(pair) != 0 {
+				return aocutil.LineErrorf(i+1, "pair has only one packet")
+			}
+			continue
+		}
+		p, err := ParsePacket(l)
+		if err != nil {
+			return aocutil.AtLine(err, i+1, l)
+		}
+		pair = append(pair, p)
+		if len(pair) == 2 {
+			s.pairs = append(s.pairs, [2]Packet{pair[0], pair[1]})
+			pair = nil
+		}
+	}
+	if len(pair) != 0 {
+		return fmt.Errorf("last pair has only one packet")
+	}
+	return nil
+}
+
+// Part 1: sum up the 1-based indices of all pairs that are in the right
+// order (s/b 13, 6484)
+func (s *solver) Part1() (string, error) {
+	ans := 0
+	for i, pair := range s.pairs {
+		if Compare(pair[0], pair[1]) < 0 {
+			ans += i + 1
+		}
+	}
+	return fmt.Sprint(ans), nil
+}
+
+// Part 2: put all the packets in the right order, after adding two
+// "divider packets", and multiply the 1-based indices of the dividers
+// (s/b 140, 19305)
+func (s *solver) Part2() (string, error) {
+
+	// Convert pairs to a list, and add the two divider packets
+	dividers := []Packet{List(List(Int(2))), List(List(Int(6)))}
+	packets := Packets{}
+	for _, pair := range s.pairs {
+		packets = append(packets, pair[0], pair[1])
+	}
+	packets = append(packets, dividers...)
+
+	// Sort, then find the dividers
+	sort.Sort(packets)
+	ans := 1
+	for _, d := range dividers {
+		i := sort.Search(len(packets), func(i int) bool { return Compare(packets[i], d) >= 0 })
+		ans *= i + 1
+	}
+	return fmt.Sprint(ans), nil
+}
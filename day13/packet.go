@@ -0,0 +1,151 @@
+// Packets for Day 13: nested lists of integers such as [1,[2,[3,4]]], which
+// Python handles with its mixed list and int types. Here a packet value is
+// either an integer or a list of values. Packets are read from text, compared
+// according to the puzzle's rules, sorted, and printed back in the same form
+// they were read.
+//
+// AK, 13 Dec 2022
+
+package day13
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"adventofcode2022/aocutil"
+)
+
+// A packet value: an integer, or a list of values (which may be empty)
+type Packet struct {
+	IsList bool
+	Num    int      // the integer, if not a list
+	List   []Packet // the values in the list, if a list
+}
+
+// Make an integer value
+func Int(n int) Packet {
+	return Packet{Num: n}
+}
+
+// Make a list value
+func List(vs ...Packet) Packet {
+	return Packet{IsList: true, List: vs}
+}
+
+// Read a packet from text, e.g., [[1],[2,3,4]]. Returns a *aocutil.ParseError
+// with the column of anything that is not valid.
+func ParsePacket(s string) (Packet, error) {
+	p, pos, err := parseValue(s, 0)
+	if err != nil {
+		return Packet{}, err
+	}
+	if pos < len(s) {
+		return Packet{}, &aocutil.ParseError{Col: pos + 1, Token: s[pos:], Err: errors.New("unexpected text after packet")}
+	}
+	return p, nil
+}
+
+// Parse a value starting at a position in the text, returning the value and
+// the position just after it
+func parseValue(s string, pos int) (Packet, int, error) {
+
+	// Integer: digits up to the next comma or bracket
+	if pos < len(s) && s[pos] != '[' {
+		end := pos
+		for end < len(s) && s[end] != ',' && s[end] != ']' {
+			end++
+		}
+		n, err := strconv.Atoi(s[pos:end])
+		if err != nil {
+			return Packet{}, pos, &aocutil.ParseError{Col: pos + 1, Token: s[pos:end], Err: aocutil.ErrInt}
+		}
+		return Int(n), end, nil
+	}
+	if pos >= len(s) {
+		return Packet{}, pos, &aocutil.ParseError{Col: pos + 1, Err: errors.New("packet ends, expected a value")}
+	}
+
+	// List: values separated by commas, up to the closing bracket
+	l := List()
+	pos++ // skip over [
+	if pos < len(s) && s[pos] == ']' {
+		return l, pos + 1, nil
+	}
+	for {
+		v, next, err := parseValue(s, pos)
+		if err != nil {
+			return Packet{}, next, err
+		}
+		l.List = append(l.List, v)
+		pos = next
+		if pos >= len(s) {
+			return Packet{}, pos, &aocutil.ParseError{Col: pos + 1, Err: errors.New("packet ends, expected ]")}
+		}
+		if s[pos] == ']' {
+			return l, pos + 1, nil
+		}
+		if s[pos] != ',' {
+			return Packet{}, pos, &aocutil.ParseError{Col: pos + 1, Token: s[pos : pos+1], Err: errors.New("expected , or ]")}
+		}
+		pos++ // skip over comma
+	}
+}
+
+// Show a packet the same way it is read, e.g., [[1],[2,3,4]]
+func (p Packet) String() string {
+	var sb strings.Builder
+	p.write(&sb)
+	return sb.String()
+}
+
+func (p Packet) write(sb *strings.Builder) {
+	if !p.IsList {
+		sb.WriteString(strconv.Itoa(p.Num))
+		return
+	}
+	sb.WriteByte('[')
+	for i, v := range p.List {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		v.write(sb)
+	}
+	sb.WriteByte(']')
+}
+
+// Compare two packets according to the puzzle's rules, returning -1 if
+// a comes before b (the "right order"), 1 if after, and 0 if they are
+// the same:
+//   - two integers: the lower one comes first
+//   - two lists: compare the values in turn, and if they are all the
+//     same, the shorter list comes first
+//   - an integer and a list: compare the integer as a list of just it
+func Compare(a, b Packet) int {
+	switch {
+	case !a.IsList && !b.IsList:
+		if a.Num < b.Num {
+			return -1
+		} else if a.Num > b.Num {
+			return 1
+		}
+		return 0
+	case !a.IsList:
+		return Compare(List(a), b)
+	case !b.IsList:
+		return Compare(a, List(b))
+	}
+	for i := 0; i < len(a.List) && i < len(b.List); i++ {
+		if c := Compare(a.List[i], b.List[i]); c != 0 {
+			return c
+		}
+	}
+	return Compare(Int(len(a.List)), Int(len(b.List)))
+}
+
+// A list of packets, which can be sorted into the right order
+type Packets []Packet
+
+func (ps Packets) Len() int           { return len(ps) }
+func (ps Packets) Less(i, j int) bool { return Compare(ps[i], ps[j]) < 0 }
+func (ps Packets) Swap(i, j int)      { ps[i], ps[j] = ps[j], ps[i] }
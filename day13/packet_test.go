@@ -0,0 +1,85 @@
+// Unit tests for the packets of Day 13
+
+package day13
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"adventofcode2022/aocutil"
+)
+
+// Printing a packet gives back the text it was read from
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{"[]", "[[]]", "[1,[2,[3,[4,[5,6,7]]]],8,9]", "[[[]],10,[0]]"} {
+		p, err := ParsePacket(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+		} else if p.String() != s {
+			t.Errorf("%s: printed as %s", s, p)
+		}
+	}
+	if p := List(Int(1), List(Int(2), List(Int(3), Int(4)))); p.String() != "[1,[2,[3,4]]]" {
+		t.Errorf("made packet printed as %s", p)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, c := range []struct{ text, err string }{
+		{"[1,2", "packet ends, expected ]"},
+		{"[1,x]", `"x": invalid integer`},
+		{"[1]]", `"]": unexpected text after packet`},
+		{"", "packet ends, expected a value"},
+		{"[[1]x2]", `"x": expected , or ]`},
+	} {
+		_, err := ParsePacket(c.text)
+		var pe *aocutil.ParseError
+		if err == nil || !strings.Contains(err.Error(), c.err) || !errors.As(err, &pe) || pe.Col == 0 {
+			t.Errorf("%q: got error %v, expected %q", c.text, err, c.err)
+		}
+	}
+}
+
+// The pairs from the puzzle, in order, and whether each is in the right order
+func TestCompare(t *testing.T) {
+	for _, c := range []struct {
+		a, b  string
+		right bool
+	}{
+		{"[1,1,3,1,1]", "[1,1,5,1,1]", true},
+		{"[[1],[2,3,4]]", "[[1],4]", true},
+		{"[9]", "[[8,7,6]]", false},
+		{"[[4,4],4,4]", "[[4,4],4,4,4]", true},
+		{"[7,7,7,7]", "[7,7,7]", false},
+		{"[]", "[3]", true},
+		{"[[[]]]", "[[]]", false},
+		{"[1,[2,[3,[4,[5,6,7]]]],8,9]", "[1,[2,[3,[4,[5,6,0]]]],8,9]", false},
+	} {
+		a, _ := ParsePacket(c.a)
+		b, _ := ParsePacket(c.b)
+		if got := Compare(a, b) < 0; got != c.right || Compare(b, a) < 0 == c.right {
+			t.Errorf("%s vs %s: right order is %v, expected %v", c.a, c.b, got, c.right)
+		}
+	}
+	if a, b := Int(3), List(Int(3)); Compare(a, b) != 0 {
+		t.Errorf("3 vs [3] should be the same")
+	}
+}
+
+func TestSort(t *testing.T) {
+	packets := Packets{}
+	for _, s := range []string{"[[2]]", "[3]", "[]", "[[1],4]", "[1,1]"} {
+		p, _ := ParsePacket(s)
+		packets = append(packets, p)
+	}
+	sort.Sort(packets)
+	got := []string{}
+	for _, p := range packets {
+		got = append(got, p.String())
+	}
+	if s := strings.Join(got, " "); s != "[] [1,1] [[1],4] [[2]] [3]" {
+		t.Errorf("sorted to %s", s)
+	}
+}
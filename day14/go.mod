@@ -1,3 +0,0 @@
-module day14
-
-go 1.19
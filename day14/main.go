@@ -8,35 +8,82 @@
 //
 // AK, 14 Dec 2022
 
-package main
+package day14
 
 import (
 	"fmt"
+	"io"
 	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/grid"
 )
 
+func init() {
+	aoc.Register(14, func() aoc.Solver { return &solver{} })
+}
+
 // A point in 2-d space
 type Point struct {
 	x, y int
 }
 
-func main() {
+// The parsed input: a list of paths, each consisting of x,y points
+type solver struct {
+	paths [][]Point
+}
 
-	// Read data into a list of paths, each consisting of x,y points
-	//fname := "sample.txt"
-	fname := "input.txt"
-	paths := [][]Point{} // a list of lists of points
-	for _, l := range readLines(fname) {
+// Read data into a list of paths, each consisting of x,y points
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	s.paths = [][]Point{} // a list of lists of points
+	for i, l := range lines {
 		path := []Point{}
-		for _, s := range strings.Split(l, " -> ") {
-			xy := strings.Split(s, ",")
-			path = append(path, Point{atoi(xy[0]), atoi(xy[1])})
+		for _, p := range strings.Split(l, " -> ") {
+			xy, err := aocutil.ParseInts(p, ",")
+			if err != nil {
+				return aocutil.AtLine(err, i+1, l)
+			}
+			if len(xy) != 2 {
+				return aocutil.LineErrorf(i+1, "invalid point %q", p)
+			}
+			path = append(path, Point{xy[0], xy[1]})
 		}
-		paths = append(paths, path)
+		s.paths = append(s.paths, path)
 	}
+	return nil
+}
+
+// Part 1: grains of sand before they start falling off the edges
+// (24, 1133)
+func (s *solver) Part1() (string, error) {
+	part1, _ := simulate(s.paths)
+	return fmt.Sprint(part1), nil
+}
+
+// Part 2: grains of sand before the hole is blocked (93, 27566)
+func (s *solver) Part2() (string, error) {
+	_, part2 := simulate(s.paths)
+	return fmt.Sprint(part2), nil
+}
+
+// What is at each point in space
+const (
+	rock = 1
+	sand = 2
+)
+
+// Run the simulation, and return the number of grains of sand before they
+// start falling off the edges (Part 1), and before the hole is blocked
+// (Part 2)
+func simulate(paths [][]Point) (int, int) {
 
 	// Turn paths into a sparse 2-d array of points already blocked by "rock"
-	space := map[Point]int{}
+	space := grid.NewSparse[int]()
 	for _, path := range paths {
 		for i := 1; i < len(path); i++ { // each segment
 
@@ -58,8 +105,8 @@ func main() {
 			// Mark each point on the segment as filled in space
 			p := p0 // this point
 			for {
-				space[p] = 1 // mark location as filled with rock
-				p.x += dx    // adjust position by one
+				space.Set(p.x, p.y, rock) // mark location as filled with rock
+				p.x += dx                 // adjust position by one
 				p.y += dy
 				if (dx > 0 && p.x > p1.x) || (dx < 0 && p.x < p1.x) ||
 					(dy > 0 && p.y > p1.y) || (dy < 0 && p.y < p1.y) {
@@ -69,25 +116,23 @@ func main() {
 		}
 	}
 
-	// Find the bottom of the existing rock, and lowest point overall
-	bottom := map[int]int{} // for Part 1: lowest point at each x
-	lowest := 0             // for Part 2: lowest point overall
-	for p, _ := range space {
-		if p.y > bottom[p.x] { // for part 1
-			bottom[p.x] = p.y
+	// Find the bottom of the existing rock at each x (for Part 1), and
+	// lowest point overall (for Part 2), where there is a "floor"
+	bottom := map[int]int{}
+	space.Each(func(p grid.Point, _ int) {
+		if p.Y > bottom[p.X] {
+			bottom[p.X] = p.Y
 		}
-		if p.y > lowest { // for part 2
-			lowest = p.y
-		}
-	}
-
-	// For Part 2, add a layer of rock at lowest - 2
-	for i := 0; i < 1000; i++ {
-		space[Point{i, lowest + 2}] = 3
+	})
+	_, max := space.Bounds()
+	floor := max.Y + 2
+	filled := func(x, y int) bool {
+		return y == floor || space.Has(x, y)
 	}
 
 	// Start simulation
 	n := 0             // number of grains released
+	part1 := 0         // answer for part 1
 	part1done := false // haven't reported part 1 yet
 	for {
 
@@ -98,22 +143,22 @@ func main() {
 		// Try to move it down, diag left or right
 		for {
 			blocked := false
-			if !filled(space, g.x, g.y+1) {
+			if !filled(g.x, g.y+1) {
 				g.y += 1
-			} else if !filled(space, g.x-1, g.y+1) {
+			} else if !filled(g.x-1, g.y+1) {
 				g.x--
 				g.y++
-			} else if !filled(space, g.x+1, g.y+1) {
+			} else if !filled(g.x+1, g.y+1) {
 				g.x++
 				g.y++
 			} else {
-				space[g] = 2 // mark as filled with a grain of sand
+				space.Set(g.x, g.y, sand) // mark as filled with a grain of sand
 				blocked = true
 			}
 
 			// Part 1: report grains of sand before start falling below edges
 			if g.y > bottom[g.x] && !part1done { // i.e., past bottom
-				fmt.Println("Part 1 (24, 1133):", n-1)
+				part1 = n - 1
 				part1done = true // so we don't report it again
 				//visualize(space)
 			}
@@ -126,31 +171,13 @@ func main() {
 
 		// Part 2: stop when grain of sand could not be moved
 		if g.x == 500 && g.y == 0 {
-			fmt.Println("Part 2 (93, 27566):", n)
 			//visualize(space) // Uncomment to see visualization
-			break
+			return part1, space.Count(sand)
 		}
 	}
 }
 
-// Determine if point in space is filled, i.e., that element in the
-// sparse matrix is not empty
-func filled(space map[Point]int, x, y int) bool {
-	_, ok := space[Point{x, y}]
-	return ok
-}
-
-// Visualize the space,for debugging
-func visualize(space map[Point]int) {
-	for r := 0; r < 20; r++ {
-		for c := 480; c < 530; c++ {
-			x, ok := space[Point{c, r}]
-			if ok && x > 0 {
-				fmt.Printf("%d", x)
-			} else {
-				fmt.Print(" ")
-			}
-		}
-		fmt.Print("|\n")
-	}
+// Visualize the space, for debugging
+func visualize(space *grid.SparseGrid[int]) {
+	fmt.Print(space.Render(grid.Style[int]{Glyphs: map[int]byte{rock: '#', sand: 'o'}, Empty: '.'}))
 }
@@ -1,3 +0,0 @@
-module day15
-
-go 1.19
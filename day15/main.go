@@ -7,14 +7,22 @@
 //
 // AK, 15 Dec 2022
 
-package main
+package day15
 
 import (
 	"fmt"
+	"io"
 	"sort"
-	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/grid"
 )
 
+func init() {
+	aoc.Register(15, func() aoc.Solver { return &solver{row: 2000000, limit: 4000000} })
+}
+
 // A position in 2-d space
 type Position struct {
 	x, y int
@@ -36,93 +44,129 @@ type Range struct {
 	lo, hi int
 }
 
-// Global list of sensors, map of beacons
-var sensors []Sensor
-var beacons map[Position]Beacon
+// The parsed input: list of sensors, map of beacons, and the row for Part 1
+// and the size of the search space for Part 2
+type solver struct {
+	sensors []Sensor
+	beacons map[Position]Beacon
+	row     int // 2000000 for the input, 10 for the sample
+	limit   int // 4000000 for the input, 20 for the sample
+}
 
-func main() {
+// The sample uses a different row and a smaller search space (set by the
+// aoc command's --sample)
+func (s *solver) UseSample() {
+	s.row, s.limit = 10, 20
+}
 
-	// Read the input file, parse into list of sensors
-	fname := "sample.txt"
-	fname = "input.txt" // uncomment to use input
-	readData(fname)
+// Options: row=n for Part 1, limit=n for the search space of Part 2 (from
+// 0 to n in both directions)
+func (s *solver) SetOption(name, value string) error {
+	n, err := aocutil.ParseInt(value)
+	if err != nil {
+		return fmt.Errorf("option %s: %w", name, err)
+	}
+	switch name {
+	case "row":
+		s.row = n
+	case "limit":
+		if n < 0 {
+			return fmt.Errorf("option limit: can't be negative, got %d", n)
+		}
+		s.limit = n
+	default:
+		return fmt.Errorf("unknown option %q", name)
+	}
+	return nil
+}
 
-	// Part 1: use 10 for sample, 2000000 for input
-	//part1(10)
-	part1(2000000)
+// Part 1: use row 10 for sample, 2000000 for input (s/b 26, 4827924)
+func (s *solver) Part1() (string, error) {
+	return fmt.Sprint(s.part1(s.row)), nil
+}
 
-	// Part 2: use 20 for sample, 4000000 for input
-	//part2(20)
-	part2(4000000)
+// Part 2: use 20 for sample, 4000000 for input (s/b 56000011)
+func (s *solver) Part2() (string, error) {
+	freq, ok := s.part2(s.limit)
+	if !ok {
+		return "", fmt.Errorf("no gap found")
+	}
+	return fmt.Sprint(freq), nil
 }
 
 // Part 1: count the positions where a beacon cannot possibly be along
 // just a single row.
-func part1(y int) { // y is the row number
+func (s *solver) part1(y int) int { // y is the row number
 
 	// Create a map of all the points covered, i.e., for each sensor,
 	// the points within the distance to its nearest beacon. For Part 1,
 	// we are only looking at one row
-	covered := map[Position]int{}
-	for _, s := range sensors {
-		d := dist(s.at, s.beacon.at) // Distance from this sensor to nearest beacon
-		for x := s.at.x - d - 1; x <= s.at.x+d+1; x++ {
+	covered := grid.NewSparse[byte]()
+	for _, sn := range s.sensors {
+		d := dist(sn.at, sn.beacon.at) // Distance from this sensor to nearest beacon
+		for x := sn.at.x - d - 1; x <= sn.at.x+d+1; x++ {
 			p := Position{x, y}
-			if dist(p, s.at) <= d {
-				covered[p] = 1
+			if dist(p, sn.at) <= d {
+				covered.Set(x, y, '#')
 			}
 		}
 	}
-	//visualize(covered)
+
+	// Mark the beacons and sensors, which are not counted
+	for _, b := range s.beacons {
+		covered.Set(b.at.x, b.at.y, 'B')
+	}
+	for _, sn := range s.sensors {
+		covered.Set(sn.at.x, sn.at.y, 'S')
+	}
+	//s.visualize(covered)
 
 	// Count the positions where a beacon cannot possibly be along
 	// just a single row
-	n := 0
-	for p, _ := range covered {
-		if p.y == y && !beaconAt(p) {
-			n++
-		}
-	}
-	fmt.Println("Part 1 (s/b 26, 4827924):", n)
+	lo, hi := covered.Bounds()
+	return covered.CountIn(grid.Point{X: lo.X, Y: y}, grid.Point{X: hi.X, Y: y}, func(c byte) bool { return c != 'B' })
 }
 
 // Find an undetected beacon, i.e., outside the space we already identified as
 // not having a beacon, within a constrained space (x & y both 0-20 for sample,
 // 0 to 4000000 for input. Compute "tuning frequency" as = x * 4000000 + y.
 // In sample, only 14,11 could have beacon, freq = 56000011
-func part2(maxXY int) {
+// Returns false if no gap was found.
+func (s *solver) part2(maxXY int) (int, bool) {
 
 	// Get ranges that are covered in each row, by computing distance from
 	// sensor to its beacon, and then tracing a diamond that covers the same
 	// distance from the sensor in all directions.
-	fmt.Println("Building coverage")
 	covered := map[int][]Range{} // row =>  [Range1, ...]
-	for _, s := range sensors {
-		d := dist(s.at, s.beacon.at)              // distance from sensor to nearest beacon
-		var w int                                 // width at tip of diamond
-		for y := s.at.y - d; y <= s.at.y+d; y++ { // each row of diamond
+	for _, sn := range s.sensors {
+		d := dist(sn.at, sn.beacon.at)              // distance from sensor to nearest beacon
+		var w int                                   // width at tip of diamond
+		for y := sn.at.y - d; y <= sn.at.y+d; y++ { // each row of diamond
 
 			// Add a range for this row
 			if y >= 0 && y <= maxXY {
-				r := Range{s.at.x - w, s.at.x + w}
+				r := Range{sn.at.x - w, sn.at.x + w}
 				covered[y] = append(covered[y], r)
 			}
 
 			// Adjust width of the diamond
-			if y < s.at.y {
+			if y < sn.at.y {
 				w++
-			} else if y >= s.at.y {
+			} else if y >= sn.at.y {
 				w--
 			}
 		}
 	}
 
 	// Now search each row for a gap between coverage
-	fmt.Println("Searching for gap (s/b 14,11)")
 	var gapX, gapY int
+	found := false
 	for r := 0; r <= maxXY; r++ {
 
 		// Get the ranges for this row, and merge overlapping
+		if len(covered[r]) == 0 { // whole row is uncovered, gap at 0,r
+			return r, true
+		}
 		lims := merge(covered[r]) // returns sorted
 
 		// Skip where only one range and covers entire row
@@ -130,32 +174,26 @@ func part2(maxXY int) {
 			continue // no gaps possible
 		}
 
-		// Gaps to left of first range (ignored for problem)?
-		if lims[0].lo > 0 {
-			fmt.Println("Row", r, ": gaps up to", lims[0].lo-1)
-		}
+		// Gaps to left of first range are ignored for problem
 
 		// Gaps between ranges?
 		// Note that we only consider gaps in the middle to be relevant
 		// for the problem.
 		for i := 1; i < len(lims); i++ {
 			if lims[i].lo-lims[i-1].hi > 1 {
-				fmt.Println("Row", r, ": gap from", lims[i-1].hi+1, "to", lims[i].lo-1)
 				gapX = lims[i-1].hi + 1
 				gapY = r
+				found = true
 				break
 			}
 		}
 
-		// Gaps after last range (ignored for problem)?
-		if lims[len(lims)-1].hi < maxXY {
-			fmt.Println("Row", r, ": gaps after", lims[len(lims)-1].hi)
-		}
+		// Gaps after last range are also ignored for problem
 	}
 
 	// Compute "frequency" for part 2 answer
 	freq := gapX*4000000 + gapY
-	fmt.Printf("Part 2 (s/b 56000011): gap at %d,%d => freq %d\n", gapX, gapY, freq)
+	return freq, found
 }
 
 // Merge overlapping ranges
@@ -182,82 +220,45 @@ func merge(ranges []Range) []Range {
 	return result
 }
 
-// Read the input file, parse into lists of sensors and beacons
-func readData(fname string) {
-
-	beacons = map[Position]Beacon{}
-	for _, l := range readLines(fname) {
+// Format of each line of the input
+var sensorLine = aocutil.MustPattern("Sensor at x={int}, y={int}: closest beacon is at x={int}, y={int}")
+
+// Read the input, parse into lists of sensors and beacons
+func (s *solver) Parse(r io.Reader) error {
+	in := aocutil.NewReader(r)
+	s.sensors = nil
+	s.beacons = map[Position]Beacon{}
+	for in.Next() {
+		if len(in.Text()) == 0 {
+			continue
+		}
 
 		// Extract the x and y positions of the sensor and beacon
-		words := strings.Split(l, " ")
-		sx := words[2][2:]
-		sy := words[3][2:]
-		spos := Position{atoi(sx[:len(sx)-1]), atoi(sy[:len(sy)-1])}
-		bx := words[8][2:]
-		by := words[9][2:]
-		bpos := Position{atoi(bx[:len(bx)-1]), atoi(by)}
+		var spos, bpos Position
+		if err := sensorLine.Match(in.Text(), &spos.x, &spos.y, &bpos.x, &bpos.y); err != nil {
+			return in.AtLine(err, 0)
+		}
 
 		// Get the beacon, create if necessary
-		b, ok := beacons[bpos]
+		b, ok := s.beacons[bpos]
 		if !ok {
 			b = Beacon{bpos}
-			beacons[bpos] = b
+			s.beacons[bpos] = b
 		}
 
 		// Add sensor to list
-		sensors = append(sensors, Sensor{spos, &b})
+		s.sensors = append(s.sensors, Sensor{spos, &b})
 	}
+	return in.Err()
 }
 
 // Manhattan distance between two positions
 func dist(a, b Position) int {
-	return abs(a.x-b.x) + abs(a.y-b.y)
-}
-
-// Is a sensor located at position?
-func sensorAt(p Position) bool {
-	for _, s := range sensors {
-		if s.at.x == p.x && s.at.y == p.y {
-			return true
-		}
-	}
-	return false
-}
-
-// Is a beacon located at position?
-func beaconAt(p Position) bool {
-	_, ok := beacons[p]
-	return ok
+	return aocutil.Abs(a.x-b.x) + aocutil.Abs(a.y-b.y)
 }
 
-// Visualize the covered map, used for debugging so not generalized
-func visualize(covered map[Position]int) {
-
-	x := make([]byte, 50, 50)  // adjust size of row as necessary
-	for y := -2; y < 23; y++ { // adjust range as necessary
-
-		// Blank out this row
-		for i := 0; i < len(x); i++ {
-			x[i] = '.'
-		}
-
-		// Mark any covered areas
-		xoff := 3 // x offset, adjust as necessary to avoid negative indexes
-		for p, _ := range covered {
-			if p.x < 0 || p.x > 20 || p.y < 0 || p.y > 20 { // adjust as nec
-				continue // restrict for part 2
-			}
-			if p.y == y {
-				if beaconAt(p) {
-					x[p.x+xoff] = 'B'
-				} else if sensorAt(p) {
-					x[p.x+xoff] = 'S'
-				} else {
-					x[p.x+xoff] = '#'
-				}
-			}
-		}
-		fmt.Printf("%2d ", y)
-		fmt.Println(string(x))
-	}
+// Show the positions covered, with beacons and sensors, over the whole
+// area the grid covers (for debugging)
+func (s *solver) visualize(covered *grid.SparseGrid[byte]) {
+	fmt.Print(covered.Render(grid.Style[byte]{Empty: '.'}))
 }
@@ -0,0 +1,197 @@
+// Bitmask dynamic programming for Day 16, for any number of actors and any
+// number of minutes. Only the valves with some flow are worth going to, so
+// they are numbered from 0 (bit i of a mask is valve i), with the shortest
+// distances between them and from AA.
+//
+// First, the routes of one actor from AA are explored, remembering the most
+// pressure released on arrival at each (position, time, opened valves), so a
+// state already reached with as much pressure is not explored again. This
+// gives the most pressure one actor can release by opening exactly each set
+// of valves. Since actors never need to open the same valve, several actors
+// just split the valves between them: the best for k actors on a set is the
+// best over each subset for one actor, plus the best for k-1 actors on the
+// rest.
+//
+// AK, Dec 2022
+
+package day16
+
+import (
+	"fmt"
+	"sort"
+
+	"adventofcode2022/graph"
+)
+
+// Most valves with flow that can be handled: the tables have 2^n entries,
+// and splitting the valves between actors goes through every subset of
+// every subset, 3^n steps for each actor (the puzzle input has 15)
+const maxValves = 16
+
+// The valves with flow, numbered for the bitmasks
+type valves struct {
+	ids   []int   // node index of each valve
+	flow  []int   // flow rate of each valve
+	dist  [][]int // minutes between valves, dist[n] is from AA
+	start int     // number for AA, i.e., n
+}
+
+// Get the valves with flow, and the distances between them and from AA
+func (s *solver) flowValves() (*valves, error) {
+	v := &valves{}
+	for i, n := range s.nodes {
+		if n.flow > 0 {
+			v.ids = append(v.ids, i)
+			v.flow = append(v.flow, n.flow)
+		}
+	}
+	if len(v.ids) > maxValves {
+		return nil, fmt.Errorf("%d valves with flow, can only do %d", len(v.ids), maxValves)
+	}
+	v.start = len(v.ids)
+	from := append(append([]int{}, v.ids...), s.nodeAA)
+	for _, a := range from {
+		row := []int{}
+		for _, b := range v.ids {
+			row = append(row, s.distances.Dist(a, b))
+		}
+		v.dist = append(v.dist, row)
+	}
+	return v, nil
+}
+
+// The best plan for a number of actors, all starting at AA, to release
+// the most pressure in a number of minutes
+func (s *solver) bestPlan(actors, minutes int) (*Plan, error) {
+	if actors < 1 || minutes < 0 {
+		return nil, fmt.Errorf("can't have %d actors for %d minutes", actors, minutes)
+	}
+	v, err := s.flowValves()
+	if err != nil {
+		return nil, err
+	}
+	best, routes := v.bestBySet(minutes)
+	sets := v.split(best, actors)
+
+	// Follow each actor's route through its valves, to see when each one
+	// is opened
+	plan := &Plan{Actors: actors, Minutes: minutes}
+	for a, set := range sets {
+		here, t := v.start, 0
+		for _, next := range routes[set] {
+			t += v.dist[here][next] + 1
+			step := Step{Minute: t, Actor: a + 1, Valve: s.nodes[v.ids[next]].id, Rate: v.flow[next]}
+			step.Pressure = step.Rate * (minutes - t)
+			plan.Steps = append(plan.Steps, step)
+			plan.Pressure += step.Pressure
+			here = next
+		}
+	}
+	sort.SliceStable(plan.Steps, func(i, j int) bool {
+		return plan.Steps[i].Minute < plan.Steps[j].Minute
+	})
+	return plan, nil
+}
+
+// Most pressure one actor can release in a number of minutes by opening
+// each set of valves (indexed by bitmask), 0 if the set can't be opened,
+// and the order in which to open the valves for each set
+func (v *valves) bestBySet(minutes int) ([]int, [][]int) {
+	best := make([]int, 1<<len(v.flow))
+	routes := make([][]int, len(best))
+	type state struct {
+		opened  uint32 // bitmask of the valves opened
+		here, t int    // where the actor is, and when
+	}
+	route := []int{}        // the valves opened so far, in order
+	seen := map[state]int{} // most pressure on reaching each state
+	var visit func(here, t int, opened uint32, pressure int)
+	visit = func(here, t int, opened uint32, pressure int) {
+
+		// Nothing new if this state was already reached with as much
+		key := state{opened, here, t}
+		if p, ok := seen[key]; ok && p >= pressure {
+			return
+		}
+		seen[key] = pressure
+		if pressure > best[opened] {
+			best[opened] = pressure
+			routes[opened] = append([]int{}, route...)
+		}
+
+		// Try going to each valve not yet opened, if there would be
+		// time to get any flow from it (takes a minute to open)
+		for next, d := range v.dist[here] {
+			if opened&(1<<next) != 0 || d == graph.Unreachable {
+				continue
+			}
+			open := t + d + 1 // time when the valve is open
+			if open >= minutes {
+				continue
+			}
+			route = append(route, next)
+			visit(next, open, opened|1<<next, pressure+v.flow[next]*(minutes-open))
+			route = route[:len(route)-1]
+		}
+	}
+	visit(v.start, 0, 0, 0)
+	return best, routes
+}
+
+// Split the valves between a number of actors, for the most pressure,
+// given the best for one actor on each set of valves, and return the set
+// of valves for each actor
+func (v *valves) split(best []int, actors int) []int {
+
+	// Best for one actor with any of the valves in each set, and which
+	// subset of the valves that is
+	within := append([]int{}, best...)
+	subset := make([]int, len(best))
+	for mask := range subset {
+		subset[mask] = mask
+	}
+	for i := range v.flow {
+		for mask := range within {
+			if mask&(1<<i) != 0 && within[mask^1<<i] > within[mask] {
+				within[mask] = within[mask^1<<i]
+				subset[mask] = subset[mask^1<<i]
+			}
+		}
+	}
+
+	// Each extra actor takes some of the valves, leaving the rest to
+	// the others (for the last one, only the set of all valves matters),
+	// remembering which valves it takes from each set
+	all := len(best) - 1
+	takes := make([][]int, actors+1)
+	for a := 2; a <= actors; a++ {
+		next := make([]int, len(best))
+		takes[a] = make([]int, len(best))
+		for mask := range next {
+			if a == actors && mask != all {
+				continue
+			}
+			for sub := mask; ; sub = (sub - 1) & mask {
+				if p := best[sub] + within[mask^sub]; p > next[mask] {
+					next[mask] = p
+					takes[a][mask] = sub
+				}
+				if sub == 0 {
+					break
+				}
+			}
+		}
+		within = next
+	}
+
+	// Work back from the last actor to the first, which has the best
+	// subset of whatever is left
+	sets := make([]int, actors)
+	mask := all
+	for a := actors; a >= 2; a-- {
+		sets[a-1] = takes[a][mask]
+		mask ^= sets[a-1]
+	}
+	sets[0] = subset[mask]
+	return sets
+}
@@ -0,0 +1,83 @@
+// Unit tests for the dynamic programming of Day 16, checked against trying
+// every way of sharing the sample's valves between the actors
+
+package day16
+
+import "testing"
+
+// Most pressure one actor can release opening some of a set of valves, by
+// trying every order
+func (v *valves) bruteOne(set []int, here, left int) int {
+	best := 0
+	for i, next := range set {
+		t := left - v.dist[here][next] - 1
+		if t <= 0 {
+			continue
+		}
+		rest := append(append([]int{}, set[:i]...), set[i+1:]...)
+		if p := v.flow[next]*t + v.bruteOne(rest, next, t); p > best {
+			best = p
+		}
+	}
+	return best
+}
+
+// Most pressure a number of actors can release, by giving each valve to
+// each actor in turn
+func (v *valves) brute(actors, minutes int) int {
+	sets := make([][]int, actors)
+	var share func(i int) int
+	share = func(i int) int {
+		if i == len(v.ids) {
+			total := 0
+			for _, set := range sets {
+				total += v.bruteOne(set, v.start, minutes)
+			}
+			return total
+		}
+		best := 0
+		for a := range sets {
+			sets[a] = append(sets[a], i)
+			if p := share(i + 1); p > best {
+				best = p
+			}
+			sets[a] = sets[a][:len(sets[a])-1]
+		}
+		return best
+	}
+	return share(0)
+}
+
+func TestBestPlan(t *testing.T) {
+	s := readSample(t)
+	v, err := s.flowValves()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1, p2 := v.brute(1, 30), v.brute(2, 26); p1 != 1651 || p2 != 1707 {
+		t.Fatalf("brute force gives %d, %d, want 1651, 1707", p1, p2)
+	}
+	for _, c := range []struct{ actors, minutes int }{
+		{1, 30}, {2, 26}, {3, 26}, {4, 26}, {3, 10}, {1, 1},
+	} {
+		want := v.brute(c.actors, c.minutes)
+		plan, err := s.bestPlan(c.actors, c.minutes)
+		if err != nil || plan.Pressure != want {
+			t.Errorf("%d actors, %d minutes: got %v, %v, want %d", c.actors, c.minutes, plan, err, want)
+			continue
+		}
+
+		// Each valve opened at most once, and the steps add up
+		opened, total := map[string]bool{}, 0
+		for _, st := range plan.Steps {
+			if opened[st.Valve] || st.Actor < 1 || st.Actor > c.actors {
+				t.Errorf("%d actors: bad step %+v", c.actors, st)
+			}
+			opened[st.Valve] = true
+			total += st.Pressure
+		}
+		if total != plan.Pressure {
+			t.Errorf("%d actors: steps add up to %d, not %d", c.actors, total, plan.Pressure)
+		}
+	}
+}
@@ -1,5 +0,0 @@
-module day16
-
-go 1.19
-
-require github.com/yourbasic/graph v0.0.0-20210606180040-8ecfec1c2869 // indirect
@@ -7,24 +7,34 @@
 // decisions (one for you and one for the "elephant") each time step, over
 // 26 minutes.
 //
-// This is an optimization problem. Used simple depth-first dynamic programming
-// solution, recursively tries each feasible candidate unopened valve
-// (maintained in a list that keeps getting smaller each recursive call)
-// excluding those for which we wouldn't have enough time to get any flow. Same
-// for Part 2, but tried all possible pairs of remaining valves, one for each
-// actor (slow but works).
+// This is an optimization problem. First used simple depth-first search,
+// recursively trying each feasible candidate unopened valve, and for Part 2
+// all possible pairs of remaining valves, one for each actor (slow but
+// worked). Now uses dynamic programming over bitmasks of the opened valves
+// (see dp.go), which works for any number of actors and minutes, e.g.,
+// "aoc run 16 --part 2 --opt actors=3 --opt minutes=22". The plans can be
+// shown as a list of which valve is opened when, and by whom, or minute by
+// minute (see plan.go), e.g., "aoc show 16 timeline2 --sample", and the
+// network can be exported with its corridors collapsed (see network.go).
 //
 // AK, 16 and 26 Dec 2022
 
-package main
+package day16
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
-	"github.com/yourbasic/graph"
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/graph"
 )
 
+func init() {
+	aoc.Register(16, func() aoc.Solver { return &solver{} })
+}
+
 // A node in the graph
 type Node struct {
 	id     string
@@ -32,245 +42,150 @@ type Node struct {
 	connTo []string
 }
 
-// Global list of nodes
-var nodes []Node
-
-// Index of node AA
-var nodeAA int
-
-// Minutes for the simulation: 30 for Part 1, 26 for Part 2
-var minutes int = 30
-
-// Global pointer to the graph
-var g *graph.Mutable
-
-// For memoization of graph shortest distances
-type Pair struct {
-	a, b int
+// The parsed input: the list of nodes and the graph connecting them
+// (labelled with the valve names), with the index of node AA and the
+// shortest distances between all nodes, and the options for Part 2
+type solver struct {
+	nodes     []Node
+	nodeAA    int
+	g         *graph.Graph[string]
+	distances *graph.AllPaths
+	actors    int // for Part 2, 2 if not set
+	minutes   int // for Part 2, 26 if not set
 }
 
-var distances map[Pair]int
-
-func main() {
-
-	// Initialize dictionary for memoization of distances
-	distances = map[Pair]int{}
-
-	// Read the input file into a graph
-	fname := "sample.txt"
-	fname = "input.txt"
-	readInput(fname)
-
-	// Part 1: optimize total flow released over 26 minutes, for only
-	// one actor
-	valves := valvesWithFlow() //  needed for both parts
-	fmt.Println("Part 1 (s/b 1651, 1647):", optimize1(nodeAA, valves, 1))
-
-	// Part 2: assume two actors, who can act in parallel opening
-	// valves, over 26 minutes instead of 30
-	minutes = 26
-	fmt.Println("Part 2 (s/b 1707, 2169):", optimize2(nodeAA, nodeAA, valves, 1, 1))
-}
-
-// Part 1: one recursive iteration of the optimization: given that
-// you are at node "here" at time "t", try to open valves in list
-// "candidates" and find the highest cumulative flow.
-func optimize1(here int, candidates []int, t int) int {
-
-	// Try each candidate, pruning the ones that would take too
-	// long to reach
-	best := 0
-	for _, vi := range candidates {
-
-		// Don't bother if not enough time to get there, i.e.,
-		// by the time you got there, you could not open the valve
-		// in time to get any flow
-		dist := shortest(here, vi) // time to get to the next valve
-		if (minutes-t)-dist < 1 {
-			continue
-		}
-
-		// Get the value of opening this candidate valve now, until the
-		// end of the simulation (takes one time step to open)
-		thisValveFlow := nodes[vi].flow * (minutes - t - dist)
-
-		// Recursively simulate moving from this node to the alternative,
-		// and optimizing from there this node
-		newCand := remove(candidates, vi) // makes a copy
-		o := optimize1(vi, newCand, t+dist+1)
-
-		// Is this the best found?
-		if thisValveFlow+o > best {
-			best = thisValveFlow + o
-		}
+// Options for Part 2: actors=n and minutes=n
+func (s *solver) SetOption(name, value string) error {
+	n, err := aocutil.ParseInt(value)
+	if err != nil {
+		return fmt.Errorf("option %s: %w", name, err)
 	}
-
-	return best
+	switch name {
+	case "actors":
+		if n < 1 {
+			return fmt.Errorf("option actors: need at least one, got %d", n)
+		}
+		s.actors = n
+	case "minutes":
+		if n < 1 {
+			return fmt.Errorf("option minutes: need at least one, got %d", n)
+		}
+		s.minutes = n
+	default:
+		return fmt.Errorf("unknown option %q", name)
+	}
+	return nil
 }
 
-// Optimization for part 2: assume two actors who can potentially
-// open valves at each step, by trying each possible combination
-// of remaining open valves at each time step
-func optimize2(here1, here2 int, candidates []int, t1, t2 int) int {
-
-	// Stop if no more time or valves left
-	if t1 > minutes || t2 > minutes || len(candidates) == 0 {
-		return 0
+// Part 1: optimize total flow released over 30 minutes, for only
+// one actor (s/b 1651, 1647)
+func (s *solver) Part1() (string, error) {
+	plan, err := s.plan(1)
+	if err != nil {
+		return "", err
 	}
+	return fmt.Sprint(plan.Pressure), nil
+}
 
-	// Each actor can visit one candidate each time step, so get
-	// a list of all possible combinations
-	// E.g., [1,2,3] => [1,2], [2,1], [1,3], [3,1], [2,3], [3,2]
-	candPairs := [][]int{}
-	for _, vi1 := range candidates {
-		if here1 < 0 {
-			vi1 = -1
-		}
-		for _, vi2 := range candidates {
-			if here2 < 0 {
-				vi2 = -1
-			}
-			if vi1 != vi2 {
-				candPairs = append(candPairs, []int{vi1, vi2})
-			}
-		}
+// Part 2: assume two actors, who can act in parallel opening
+// valves, over 26 minutes instead of 30 (s/b 1707, 2169)
+func (s *solver) Part2() (string, error) {
+	plan, err := s.plan(2)
+	if err != nil {
+		return "", err
 	}
+	return fmt.Sprint(plan.Pressure), nil
+}
 
-	// For each candidate valve pair, try each combination of you
-	// or the elephant doing it
-	best := 0
-	for _, pair := range candPairs {
-
-		// The candidate valves for you and the elephant
-		vi1 := pair[0]
-		vi2 := pair[1]
-
-		// Don't bother if not enough time to get there
-		// (we set valve ID to -1 to indicate don't go there)
-		var dist1, dist2 int
-		if here1 >= 0 && vi1 >= 0 {
-			dist1 = shortest(here1, vi1) // time to get to the next valve
-			if (minutes-t1)-dist1 < 1 {
-				vi1 = -1
-			}
-		}
-		if here2 >= 0 && vi2 >= 0 {
-			dist2 = shortest(here2, vi2)
-			if (minutes-t2)-dist2 < 1 {
-				vi2 = -1
-			}
-		}
-		if vi1 < 0 && vi2 < 0 {
-			continue
-		}
-
-		// Get the value of opening these two candidate valves now, until the
-		// end of the simulation (takes one time step to open)
-		thisValveFlow := 0
-		if here1 >= 0 && vi1 >= 0 {
-			thisValveFlow += nodes[vi1].flow * (minutes - t1 - dist1)
-		}
-		if here2 >= 0 && vi2 >= 0 {
-			thisValveFlow += nodes[vi2].flow * (minutes - t2 - dist2)
-		}
-
-		// Recursively simulate moving from this node to the alternative,
-		// and optimizing from there this node
-		newCand := remove(candidates, vi1) // makes a copy
-		newCand = remove(newCand, vi2)
-		var o int
-		if (vi1 >= 0 || vi2 >= 0) && len(newCand) > 0 {
-			o = optimize2(vi1, vi2, newCand, t1+dist1+1, t2+dist2+1)
-		}
-
-		// Is this the best found?
-		if thisValveFlow+o > best {
-			best = thisValveFlow + o
-		}
+// The best plan for Part 1 (one actor for 30 minutes) or Part 2 (two
+// actors for 26 minutes, unless the options say otherwise)
+func (s *solver) plan(part int) (*Plan, error) {
+	if part == 1 {
+		return s.bestPlan(1, 30)
 	}
-
-	return best
+	actors := aocutil.IfElse(s.actors > 0, s.actors, 2)
+	minutes := aocutil.IfElse(s.minutes > 0, s.minutes, 26)
+	return s.bestPlan(actors, minutes)
 }
 
-// Get a list of the indices of all valves that have non-zero flow,
-// since only these are of interest during the optimization (zero-flow
-// valves only add time, they are not destinations)
-func valvesWithFlow() []int {
-	valves := []int{}
-	for i := 0; i < len(nodes); i++ {
-		if nodes[i].flow > 0 {
-			valves = append(valves, i)
-		}
-	}
-	return valves
+// Ways of showing the input: the best plan for Part 1 or 2 as a list of
+// the valves opened, or minute by minute, or the network of valves as a
+// Graphviz graph or JSON (see network.go), or some statistics about it
+func (s *solver) Formats() []string {
+	return []string{"plan1", "plan2", "timeline1", "timeline2", "dot", "json", "stats"}
 }
 
-// Shortest distance between two nodes, with memoization
-func shortest(a, b int) int {
-	pair := Pair{a, b}
-	dist, ok := distances[pair]
-	if !ok {
-		_, d := graph.ShortestPath(g, a, b)
-		dist = int(d)
-		distances[pair] = dist
+// Show the input in one of the formats
+func (s *solver) Show(w io.Writer, format string) error {
+	switch format {
+	case "dot":
+		s.dot(w)
+		return nil
+	case "json":
+		return s.writeJSON(w)
+	case "stats":
+		return s.stats(w)
+	}
+	plan, err := s.plan(aocutil.IfElse(strings.HasSuffix(format, "1"), 1, 2))
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(format, "timeline") {
+		s.timeline(w, plan)
+	} else {
+		plan.Write(w)
 	}
-	return dist
+	return nil
 }
 
-// Parse input, create graph
-func readInput(filename string) {
+// Format of each line of the input, e.g.,
+// Valve CC has flow rate=2; tunnels lead to valves DD, BB
+var valveLine = aocutil.MustPattern("Valve {word} has flow rate={int}; {_} {_} to {_} {words}")
 
-	// Read the file, one line per node
-	lines := readLines(filename)
-	fmt.Println(len(lines), "lines read")
+// Parse input, create graph
+func (s *solver) Parse(r io.Reader) error {
 
-	// Process each line, build list of nodes
-	// Valve CC has flow rate=2; tunnels lead to valves DD, BB
-	nodes = []Node{} // initialize global variable
-	nodeIndex := map[string]int{}
-	for _, l := range lines {
-		words := strings.Split(l, " ")
-		rate := atoi(words[4][5:(len(words[4]) - 1)])
-		n := Node{id: words[1], flow: rate}
-		nodeIndex[n.id] = len(nodes)  // index of this node
-		for _, c := range words[9:] { // list of connected nodes
-			if c[len(c)-1] == ',' { // remove comma
-				c = c[:len(c)-1]
-			}
-			n.connTo = append(n.connTo, strings.TrimSpace(c))
-		}
-		nodes = append(nodes, n)
+	// Read the input, one line per node
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
 	}
 
-	// Find the first node AA (global variable)
-	nodeAA = -1 // index of node we're at, start at AA
-	for i := 0; i < len(nodes); i++ {
-		if nodes[i].id == "AA" {
-			nodeAA = i
+	// Process each line, build list of nodes, and a graph node for each
+	// one (with the same index)
+	s.nodes = []Node{}
+	s.g = graph.New[string]()
+	for i, l := range lines {
+		n := Node{}
+		if err := valveLine.Match(l, &n.id, &n.flow, &n.connTo); err != nil {
+			return aocutil.AtLine(err, i+1, l)
 		}
+		if _, ok := s.g.Node(n.id); ok {
+			return aocutil.LineErrorf(i+1, "valve %s appears twice", n.id)
+		}
+		s.g.AddNode(n.id)
+		s.nodes = append(s.nodes, n)
 	}
-	if nodeAA < 0 {
-		panic("Node AA not found!")
+
+	// Find the first node AA
+	var ok bool
+	s.nodeAA, ok = s.g.Node("AA")
+	if !ok {
+		return fmt.Errorf("node AA not found")
 	}
 
-	// Great a graph for the nodes
-	g = graph.New(len(nodes))
-	for ni := 0; ni < len(nodes); ni++ {
-		n := nodes[ni]
+	// Connect the nodes in the graph
+	for ni, n := range s.nodes {
 		for _, c := range n.connTo {
-			ci := nodeIndex[c]
-			g.AddBothCost(ni, ci, 1) // add bidirectional connection, weight 1
+			ci, ok := s.g.Node(c)
+			if !ok {
+				return fmt.Errorf("valve %s leads to unknown valve %s", n.id, c)
+			}
+			s.g.AddBoth(ni, ci, 1) // add bidirectional connection, weight 1
 		}
 	}
-}
 
-// Remove element from a list, returning new copy
-func remove(elems []int, e int) []int {
-	res := []int{}
-	for _, x := range elems {
-		if x != e {
-			res = append(res, x)
-		}
-	}
-	return res
+	// Shortest distances between all pairs of valves
+	s.distances = s.g.FloydWarshall()
+	return nil
 }
@@ -0,0 +1,233 @@
+// The valve network of Day 16, for looking at: exported as a Graphviz
+// graph or as JSON, with the corridors of valves that have no flow
+// collapsed into one tunnel (weighted by its length) between the valves
+// that matter, and some statistics about it.
+//
+// AK, Dec 2022
+
+package day16
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"adventofcode2022/graph"
+)
+
+// Is a valve worth keeping in the collapsed network? (AA, where everyone
+// starts, and the valves with flow)
+func (s *solver) keep(n int) bool {
+	return n == s.nodeAA || s.nodes[n].flow > 0
+}
+
+// The network with each corridor of valves with no flow collapsed into one
+// tunnel, whose cost is the length of the corridor (only the shortest
+// corridor between two valves is kept)
+func (s *solver) collapsed() *graph.Graph[string] {
+	g := graph.New[string]()
+	for i, n := range s.nodes {
+		if s.keep(i) {
+			g.AddNode(n.id)
+		}
+	}
+
+	// From each valve kept, search through the valves with no flow, and
+	// stop at the next valves kept (each tunnel is found from both ends,
+	// so only add it from the valve that comes first)
+	for i := range s.nodes {
+		if !s.keep(i) {
+			continue
+		}
+		from, _ := g.Node(s.nodes[i].id)
+		dist := map[int]int{i: 0}
+		queue := []int{i}
+		for len(queue) > 0 {
+			here := queue[0]
+			queue = queue[1:]
+			for _, e := range s.g.Edges(here) {
+				if _, ok := dist[e.To]; ok {
+					continue
+				}
+				dist[e.To] = dist[here] + e.Cost
+				if !s.keep(e.To) {
+					queue = append(queue, e.To)
+				} else if to, _ := g.Node(s.nodes[e.To].id); to > from {
+					g.AddBoth(from, to, dist[e.To])
+				}
+			}
+		}
+	}
+	return g
+}
+
+// The tunnels of a network, each once (from the node with the lower index,
+// even if the input lists it from both ends)
+func tunnels(g *graph.Graph[string]) [][3]int {
+	var ts [][3]int
+	seen := map[[2]int]bool{}
+	for a := 0; a < g.Len(); a++ {
+		for _, e := range g.Edges(a) {
+			if e.To > a && !seen[[2]int{a, e.To}] {
+				seen[[2]int{a, e.To}] = true
+				ts = append(ts, [3]int{a, e.To, e.Cost})
+			}
+		}
+	}
+	return ts
+}
+
+// Flow rate of a valve, by name
+func (s *solver) flow(id string) int {
+	n, _ := s.g.Node(id)
+	return s.nodes[n].flow
+}
+
+// Write the collapsed network as a Graphviz graph (e.g., to make a picture
+// of it with "neato -Tsvg"), with the flow rate of each valve, and the
+// length of each tunnel
+func (s *solver) dot(w io.Writer) {
+	g := s.collapsed()
+	fmt.Fprintln(w, "graph valves {")
+	for n := 0; n < g.Len(); n++ {
+		id := g.Label(n)
+		shape := "ellipse"
+		if id == "AA" {
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(w, "\t%s [label=\"%s\\n%d\" shape=%s];\n", id, id, s.flow(id), shape)
+	}
+	for _, t := range tunnels(g) {
+		fmt.Fprintf(w, "\t%s -- %s [label=%d];\n", g.Label(t[0]), g.Label(t[1]), t[2])
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// The collapsed network as JSON
+type jsonNetwork struct {
+	Valves  []jsonValve  `json:"valves"`
+	Tunnels []jsonTunnel `json:"tunnels"`
+}
+
+type jsonValve struct {
+	ID   string `json:"id"`
+	Flow int    `json:"flow"`
+}
+
+type jsonTunnel struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Length int    `json:"length"`
+}
+
+// Write the collapsed network as JSON, with a list of valves and their
+// flow rates, and a list of tunnels and their lengths
+func (s *solver) writeJSON(w io.Writer) error {
+	g := s.collapsed()
+	net := jsonNetwork{Valves: []jsonValve{}, Tunnels: []jsonTunnel{}}
+	for n := 0; n < g.Len(); n++ {
+		net.Valves = append(net.Valves, jsonValve{g.Label(n), s.flow(g.Label(n))})
+	}
+	for _, t := range tunnels(g) {
+		net.Tunnels = append(net.Tunnels, jsonTunnel{g.Label(t[0]), g.Label(t[1]), t[2]})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(net)
+}
+
+// Upper bound on the pressure that can be released, found greedily: every
+// valve is as close as the nearest one, so the actors open the valves in
+// order of flow rate, as fast as they could possibly get to them
+func (s *solver) upperBound(actors, minutes int) (int, error) {
+	v, err := s.flowValves()
+	if err != nil {
+		return 0, err
+	}
+
+	// Shortest time to the first valve, and between two valves
+	first, between := minutes, minutes
+	rates := []int{}
+	for i, d := range v.dist[v.start] {
+		if d == graph.Unreachable {
+			continue
+		}
+		rates = append(rates, v.flow[i])
+		if d < first {
+			first = d
+		}
+		for j, d := range v.dist[i] {
+			if j != i && d != graph.Unreachable && d < between {
+				between = d
+			}
+		}
+	}
+
+	// Each actor opens its first valve after getting to the nearest one,
+	// and the next ones as soon as it could get to them
+	sort.Sort(sort.Reverse(sort.IntSlice(rates)))
+	bound := 0
+	for i, r := range rates {
+		open := first + 1 + (i/actors)*(between+1)
+		if open >= minutes {
+			break
+		}
+		bound += r * (minutes - open)
+	}
+	return bound, nil
+}
+
+// Write some statistics about the network: its size, before and after
+// collapsing corridors, its diameter (the longest of the shortest paths
+// between valves), any valves that can't be reached from AA, and how far
+// the greedy upper bound is from the best plan for each part
+func (s *solver) stats(w io.Writer) error {
+	withFlow := 0
+	for _, n := range s.nodes {
+		if n.flow > 0 {
+			withFlow++
+		}
+	}
+	fmt.Fprintf(w, "Valves: %d (%d with flow), tunnels: %d\n", len(s.nodes), withFlow, len(tunnels(s.g)))
+	c := s.collapsed()
+	fmt.Fprintf(w, "Collapsed: %d valves, %d tunnels\n", c.Len(), len(tunnels(c)))
+
+	// Diameter, and valves that can't be reached
+	diam, da, db := 0, s.nodeAA, s.nodeAA
+	unreachable := []string{}
+	for a := range s.nodes {
+		if s.distances.Dist(s.nodeAA, a) == graph.Unreachable {
+			unreachable = append(unreachable, s.nodes[a].id)
+		}
+		for b := range s.nodes {
+			if d := s.distances.Dist(a, b); d > diam {
+				diam, da, db = d, a, b
+			}
+		}
+	}
+	fmt.Fprintf(w, "Diameter: %d (%s to %s)\n", diam, s.nodes[da].id, s.nodes[db].id)
+	if len(unreachable) == 0 {
+		fmt.Fprintln(w, "Unreachable from AA: none")
+	} else {
+		fmt.Fprintf(w, "Unreachable from AA: %v\n", unreachable)
+	}
+
+	// Greedy upper bound, compared with the best plan
+	for part := 1; part <= 2; part++ {
+		plan, err := s.plan(part)
+		if err != nil {
+			return err
+		}
+		bound, err := s.upperBound(plan.Actors, plan.Minutes)
+		if err != nil {
+			return err
+		}
+		gap := 0.0
+		if plan.Pressure > 0 {
+			gap = 100 * float64(bound-plan.Pressure) / float64(plan.Pressure)
+		}
+		fmt.Fprintf(w, "Part %d: greedy upper bound %d, best %d (%.1f%% over)\n", part, bound, plan.Pressure, gap)
+	}
+	return nil
+}
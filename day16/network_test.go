@@ -0,0 +1,31 @@
+// Unit tests for the valve network of Day 16
+
+package day16
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCollapsed(t *testing.T) {
+	s := readSample(t)
+	g := s.collapsed()
+	got := []string{}
+	for _, tn := range tunnels(g) {
+		got = append(got, fmt.Sprintf("%s-%s %d", g.Label(tn[0]), g.Label(tn[1]), tn[2]))
+	}
+	want := "AA-DD 1, AA-BB 1, AA-JJ 2, BB-CC 1, CC-DD 1, DD-EE 1, EE-HH 3"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("collapsed tunnels are %s, want %s", strings.Join(got, ", "), want)
+	}
+
+	// The greedy bound is never less than the best plan
+	for actors := 1; actors <= 3; actors++ {
+		plan, _ := s.bestPlan(actors, 26)
+		bound, _ := s.upperBound(actors, 26)
+		if bound < plan.Pressure {
+			t.Errorf("%d actors: bound %d is less than best %d", actors, bound, plan.Pressure)
+		}
+	}
+}
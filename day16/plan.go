@@ -0,0 +1,106 @@
+// Plans for Day 16: which valve each actor opens in which minute, shown
+// as a list, or minute by minute like the walkthrough in the puzzle, to
+// check an answer or find out what is wrong with one.
+//
+// AK, Dec 2022
+
+package day16
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// A plan for opening the valves, with the total pressure released
+type Plan struct {
+	Actors   int    // number of actors
+	Minutes  int    // time available
+	Steps    []Step // valves opened, in order of time
+	Pressure int    // total pressure released
+}
+
+// One valve opened by one actor
+type Step struct {
+	Minute   int    // the minute during which the valve is opened
+	Actor    int    // 1 for you, 2 for the elephant, and so on
+	Valve    string // name of the valve
+	Rate     int    // flow rate of the valve
+	Pressure int    // pressure it releases from the next minute to the end
+}
+
+// An actor doing something, as in the puzzle, e.g., "You open" or "The
+// elephant opens" (or "Elephant 2 opens" if there are more)
+func (p *Plan) does(a int, verb string) string {
+	switch {
+	case a == 1:
+		return "You " + verb
+	case a == 2 && p.Actors == 2:
+		return "The elephant " + verb + "s"
+	}
+	return fmt.Sprintf("Elephant %d %ss", a-1, verb)
+}
+
+// Write the plan as a list of the valves opened, e.g.,
+//
+//	Minute  2: You open valve DD (rate 20), releasing 560
+func (p *Plan) Write(w io.Writer) {
+	for _, st := range p.Steps {
+		fmt.Fprintf(w, "Minute %2d: %s valve %s (rate %d), releasing %d\n",
+			st.Minute, p.does(st.Actor, "open"), st.Valve, st.Rate, st.Pressure)
+	}
+	fmt.Fprintf(w, "Total pressure released in %d minutes: %d\n", p.Minutes, p.Pressure)
+}
+
+// Write the plan minute by minute, like the walkthrough in the puzzle,
+// with the valves open, the pressure released, and where each actor moves
+// to (along the shortest path) or which valve it opens
+func (s *solver) timeline(w io.Writer, p *Plan) {
+
+	// What each actor does in each minute (index 0 is minute 1)
+	actions := make([][]string, p.Actors)
+	here := make([]int, p.Actors)
+	for a := range here {
+		here[a] = s.nodeAA
+	}
+	for _, st := range p.Steps {
+		a := st.Actor - 1
+		to, _ := s.g.Node(st.Valve)
+		for _, n := range s.distances.Path(here[a], to)[1:] {
+			actions[a] = append(actions[a], "move to valve "+s.nodes[n].id)
+		}
+		actions[a] = append(actions[a], "open valve "+st.Valve)
+		here[a] = to
+	}
+
+	// Go through the minutes, releasing pressure from the valves opened
+	// in earlier minutes
+	open := []string{}
+	rate, total, next := 0, 0, 0
+	for m := 1; m <= p.Minutes; m++ {
+		fmt.Fprintf(w, "== Minute %d ==\n", m)
+		total += rate
+		switch len(open) {
+		case 0:
+			fmt.Fprintln(w, "No valves are open.")
+		case 1:
+			fmt.Fprintf(w, "Valve %s is open, releasing %d pressure (%d so far).\n", open[0], rate, total)
+		default:
+			fmt.Fprintf(w, "Valves %s are open, releasing %d pressure (%d so far).\n", strings.Join(open, ", "), rate, total)
+		}
+		for a := range actions {
+			if m <= len(actions[a]) {
+				verb, rest, _ := strings.Cut(actions[a][m-1], " ")
+				fmt.Fprintf(w, "%s %s.\n", p.does(a+1, verb), rest)
+			}
+		}
+		for ; next < len(p.Steps) && p.Steps[next].Minute == m; next++ {
+			open = append(open, p.Steps[next].Valve)
+			rate += p.Steps[next].Rate
+		}
+		sort.Strings(open)
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "Total pressure released: %d\n", total)
+}
@@ -0,0 +1,69 @@
+// Unit tests for the plans of Day 16
+
+package day16
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Read the sample input
+func readSample(t *testing.T) *solver {
+	f, err := os.Open("sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s := &solver{}
+	if err := s.Parse(f); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestPlan(t *testing.T) {
+	s := readSample(t)
+
+	// Part 1 is the same as the walkthrough in the puzzle
+	plan, err := s.plan(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, st := range plan.Steps {
+		got = append(got, fmt.Sprintf("%d %s", st.Minute, st.Valve))
+	}
+	want := "2 DD, 5 BB, 9 JJ, 17 HH, 21 EE, 24 CC"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("Part 1 plan is %s, want %s", strings.Join(got, ", "), want)
+	}
+
+	// The steps add up to the total, for any number of actors, and each
+	// valve is only opened once
+	for actors := 1; actors <= 4; actors++ {
+		plan, err := s.bestPlan(actors, 26)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total, opened := 0, map[string]bool{}
+		for _, st := range plan.Steps {
+			if opened[st.Valve] {
+				t.Errorf("%d actors: valve %s opened twice", actors, st.Valve)
+			}
+			opened[st.Valve] = true
+			total += st.Pressure
+		}
+		if total != plan.Pressure {
+			t.Errorf("%d actors: steps add up to %d, not %d", actors, total, plan.Pressure)
+		}
+	}
+
+	// The timeline ends with the same total
+	var sb strings.Builder
+	s.timeline(&sb, plan)
+	if !strings.HasSuffix(sb.String(), "Total pressure released: 1651\n") {
+		t.Errorf("timeline does not add up to 1651")
+	}
+}
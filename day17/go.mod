@@ -1,3 +0,0 @@
-module day17
-
-go 1.19
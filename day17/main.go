@@ -3,168 +3,196 @@
 // Simulate simple geometric shapes falling down a shaft, getting moved left
 // and right by gusts of "gas", and falling on top of each other. For part 1,
 // determine the total height of the shapes after 2022 have fallen. For Part 2,
-// do the same for 1 000 000 000 000 shapes (infeasible to simulate, need to
-// find a better way, perhaps by finding when the pattern starts to repeat).
+// do the same for 1 000 000 000 000 shapes (infeasible to simulate, so find
+// when the tower starts to repeat, from the shape of its top and where we are
+// in the rocks and the gas pattern, and skip over the repeats).
 //
 // AK, 17 Dec 2022
 
-package main
+package day17
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
+	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/cycle"
+	"adventofcode2022/grid"
 )
 
-// A position in 2-d space
-type Point struct {
-	x, y int64
-}
-
-// Rocks in the chamber (sparse matrix)
-var chamber map[Point]byte
-
-func main() {
-
-	// Read the input file, just one line, but be sure to
-	// remove trailing newlines with editor
-	fname := "sample.txt"
-	fname = "input.txt"
-	patt, _ := ioutil.ReadFile(fname) // returns array of bytes
-	fmt.Println("Pattern length is", len(patt))
-
-	// Five rock shapes, expressed as pseudo-matrices
-	minus := [][]int{[]int{1, 1, 1, 1}}
-	plus := [][]int{[]int{0, 1, 0}, []int{1, 1, 1}, []int{0, 1, 0}}
-	L := [][]int{[]int{0, 0, 1}, []int{0, 0, 1}, []int{1, 1, 1}}
-	I := [][]int{[]int{1}, []int{1}, []int{1}, []int{1}}
-	square := [][]int{[]int{1, 1}, []int{1, 1}}
-	shapes := [][][]int{minus, plus, L, I, square}
-
-	// Simulate the falling of rocks to the bottom of a chamber 7-wide
-	chamber = map[Point]byte{} // initialize map used as sparse matrix
-	nextShape := 0             // type of the next rock
-	var height int64 = 0       // current height of the hightest rock
-	pi := 0                    // start in position 0 of pattern
-	var rocks int64 = 20000    // 2022 for Part 1, longer for Part 2
-	var rock int64             // the current rock
-	var prevHeight int64       // previous height of chamber, so we can calculate deltas for part 2
-	deltas := []int64{}        // height added by each rock during simulation
-	for rock = 1; rock <= rocks; rock++ {
-
-		// Get the shape of this rock
-		shape := shapes[nextShape]
-		nextShape++
-		if nextShape >= len(shapes) {
-			nextShape = 0
-		}
+func init() {
+	aoc.Register(17, func() aoc.Solver { return &solver{} })
+}
 
-		// Simulate appearance of the new rock: each rock appears so that its
-		// left edge is two units away from the left wall and its *bottom* edge
-		// is three units above the highest rock in the room (or the floor, if
-		// there isn't one)
-		var x int64 = 3
-		var y int64 = height + int64(3+len(shape))
-
-		// Simulate movement/falling of rock
-		for {
-
-			// Get the direction of next gas burst
-			gas := patt[pi]
-			pi++
-			if pi >= len(patt) {
-				pi = 0
-			}
+// Rocks in the chamber (sparse matrix, y is the height above the floor,
+// starting at 1)
+type Chamber struct {
+	*grid.SparseGrid[byte]
+}
 
-			// Move left/right according to gas burst, if possible
-			assert(gas == '<' || gas == '>', "Bad pattern symbol!")
-			if gas == '<' {
-				if x > 1 && !occupied(x-1, y, shape) {
-					x--
-				}
-			} else if gas == '>' {
-				if x+int64(len(shape[0]))-1 < 7 && !occupied(x+1, y, shape) {
-					x++
-				}
-			}
+// The parsed input: the pattern of gas bursts
+type solver struct {
+	patt []byte
+}
 
-			// Fall if possible, stop this rock if not
-			y-- // adjust y down
-			if y-int64(len(shape)) < 0 || occupied(x, y, shape) {
-				y++ // move back up
-				placeShape(x, y, shape)
-				if y > height {
-					height = y
-				}
-				break
-			}
+// Read the input, just one line, remove trailing newline
+func (s *solver) Parse(r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.patt = []byte(strings.TrimSpace(string(data)))
+	for i, gas := range s.patt {
+		if gas != '<' && gas != '>' {
+			return &aocutil.ParseError{Line: 1, Col: i + 1, Token: string(gas), Err: aocutil.ErrFormat}
 		}
+	}
+	if len(s.patt) == 0 {
+		return fmt.Errorf("empty pattern")
+	}
+	return nil
+}
 
-		// Part 1 is the answer at 2022 rocks, but continue simulation for part 2
-		if rock == 2022 {
-			fmt.Println("Part 1 (s/b 3068, 3114):", height)
-		}
+// Part 1 is the height after 2022 rocks (s/b 3068, 3114)
+func (s *solver) Part1() (string, error) {
+	height, err := heightAfter(s.patt, 2022)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(height), nil
+}
 
-		// Show difference in height from previous iteration
-		deltas = append(deltas, height-prevHeight)
-		prevHeight = height
+// Part 2 is the height after 1 000 000 000 000 rocks, too many to
+// simulate, so find where the tower starts repeating itself
+// (s/b 1514285714288, 1540804597682)
+func (s *solver) Part2() (string, error) {
+	height, err := heightAfter(s.patt, 1000000000000)
+	if err != nil {
+		return "", err
 	}
+	return fmt.Sprint(height), nil
+}
 
-	// Part 2: find repeating patterns, create output that be copied
-	// to separate Python script
-	// part2(deltas)  //  uncomment to run this (lots of noisy output)
-}
-
-// Part 2 is computed using a separate Python script, but this function
-// finds the repeating blocks in sequence of height deltas created during
-// the simulation. You need to copy the relevant numbers from the top
-// of the output into the Python script to get the Part 2 answer.
-// TODO: Find pattern and run calculations for Part 2 here, rather
-// than in Python script.
-func part2(deltas []int64) {
-
-	// l is the length of the pattern, start small and adjust to
-	// the value of the gaps between blocks found
-	l := 1740 // we found 1740 for input data
-
-	// Start looking for repeating patterns from the beginning of the deltas
-	for from := 0; from < len(deltas); from++ { // pattern starts here
-		seq := deltas[from : from+l] // sequence to look for
-		var prevFind, finds, firstFind int
-		gaps := []int{}                      // for gaps between sequences found
-		for i := 0; i < len(deltas)-l; i++ { // start searching from the beginning
-			this := deltas[i : i+l] // this extract
-			if same(seq, this) {    // if it matches,
-				if firstFind == 0 { // remember location of first match
-					firstFind = i
-				}
-				if prevFind > 0 { // add distance from previous find to list of gaps
-					gap := i - prevFind
-					gaps = append(gaps, gap)
-				}
-				prevFind = i // remember location of last find
-				finds++      // add up the number of finds
+// Five rock shapes, expressed as pseudo-matrices
+var shapes = [][][]int{
+	{{1, 1, 1, 1}},                    // minus
+	{{0, 1, 0}, {1, 1, 1}, {0, 1, 0}}, // plus
+	{{0, 0, 1}, {0, 0, 1}, {1, 1, 1}}, // L
+	{{1}, {1}, {1}, {1}},              // I
+	{{1, 1}, {1, 1}},                  // square
+}
+
+// The state of the simulation: the rocks that have fallen into a chamber
+// 7 wide, which rock and gas burst come next, and the height of the tower
+type tower struct {
+	chamber   Chamber
+	patt      []byte   // the pattern of gas bursts
+	nextShape int      // type of the next rock
+	pi        int      // position of next gas burst in pattern
+	height    int64    // current height of the highest rock
+	colTop    [8]int64 // height of the highest rock in each column 1-7
+}
+
+// A fingerprint of the state of the tower after a rock has come to rest:
+// the shape of its top (how far down from the highest rock the top of each
+// column is), and which rock and gas burst come next. When the same
+// fingerprint comes up again, everything from then on repeats.
+type fingerprint struct {
+	profile   [7]int64
+	nextShape int
+	pi        int
+}
+
+// Height of the tower after a number of rocks have fallen: simulate until
+// the tower repeats itself, then work out the height from how much it grows
+// in each cycle (gives up if it does not repeat within a million rocks)
+func heightAfter(patt []byte, rocks int64) (int64, error) {
+	sim := cycle.Sim[*tower, fingerprint]{
+		Start:    &tower{chamber: Chamber{grid.NewSparse[byte]()}, patt: patt},
+		Step:     func(t *tower) *tower { t.drop(); return t },
+		Key:      (*tower).fingerprint,
+		Counters: func(t *tower) []int64 { return []int64{t.height} },
+		Limit:    1000000,
+	}
+	c, err := sim.FindMap()
+	if err != nil {
+		return 0, err
+	}
+	return c.At(rocks)[0], nil
+}
+
+// Simulate the falling of one rock, until it comes to rest
+func (t *tower) drop() {
+
+	// Get the shape of this rock
+	shape := shapes[t.nextShape]
+	t.nextShape = (t.nextShape + 1) % len(shapes)
+
+	// Simulate appearance of the new rock: each rock appears so that its
+	// left edge is two units away from the left wall and its *bottom* edge
+	// is three units above the highest rock in the room (or the floor, if
+	// there isn't one)
+	var x int64 = 3
+	var y int64 = t.height + int64(3+len(shape))
+
+	// Simulate movement/falling of rock
+	for {
+
+		// Get the direction of next gas burst
+		gas := t.patt[t.pi]
+		t.pi = (t.pi + 1) % len(t.patt)
+
+		// Move left/right according to gas burst, if possible
+		if gas == '<' {
+			if x > 1 && !t.chamber.occupied(x-1, y, shape) {
+				x--
+			}
+		} else if gas == '>' {
+			if x+int64(len(shape[0]))-1 < 7 && !t.chamber.occupied(x+1, y, shape) {
+				x++
 			}
 		}
 
-		// If more than one find, probably the pattern, so output info so
-		//  it can be copied to the Python script
-		if finds > 2 {
-			fmt.Println("Sequence found", finds, "times:")
-			fmt.Println("  starts at", from, ", first found at", firstFind)
-			fmt.Println("  gaps =", gaps)
-			fmt.Printf("  sequence is %d long, sum = %d\n", len(seq), sum(seq))
-			fmt.Printf("  prefix is %d long, sum = %d\n", firstFind, sum(deltas[:firstFind]))
-			fmt.Println("Sequence:", seq) // for Python script
+		// Fall if possible, stop this rock if not
+		y-- // adjust y down
+		if y-int64(len(shape)) < 0 || t.chamber.occupied(x, y, shape) {
+			y++ // move back up
+			t.chamber.placeShape(x, y, shape)
+			for sx := 0; sx < len(shape[0]); sx++ {
+				for sy := 0; sy < len(shape); sy++ {
+					if shape[sy][sx] == 1 { // top of shape in this column
+						if y-int64(sy) > t.colTop[x+int64(sx)] {
+							t.colTop[x+int64(sx)] = y - int64(sy)
+						}
+						break
+					}
+				}
+			}
+			_, top := t.chamber.Bounds()
+			t.height = int64(top.Y)
+			return
 		}
 	}
 }
 
+// Fingerprint of the current state of the tower
+func (t *tower) fingerprint() fingerprint {
+	fp := fingerprint{nextShape: t.nextShape, pi: t.pi}
+	for x := 1; x <= 7; x++ {
+		fp.profile[x-1] = t.height - t.colTop[x]
+	}
+	return fp
+}
+
 // Check if position is occupied by a rock of given shape,
 // i.e., if any point touches
-func occupied(x, y int64, shape [][]int) bool {
+func (chamber Chamber) occupied(x, y int64, shape [][]int) bool {
 	for sy := 0; sy < len(shape); sy++ {
 		for sx := 0; sx < len(shape[0]); sx++ {
-			if shape[sy][sx] == 1 && filled(x+int64(sx), y-int64(sy)) {
+			if shape[sy][sx] == 1 && chamber.filled(x+int64(sx), y-int64(sy)) {
 				return true
 			}
 		}
@@ -173,19 +201,29 @@ func occupied(x, y int64, shape [][]int) bool {
 }
 
 // Place shape at position
-func placeShape(x, y int64, shape [][]int) {
+func (chamber Chamber) placeShape(x, y int64, shape [][]int) {
 	for sy := 0; sy < len(shape); sy++ {
 		for sx := 0; sx < len(shape[0]); sx++ {
 			if shape[sy][sx] == 1 {
-				p := Point{x + int64(sx), y - int64(sy)}
-				chamber[p] = 1
+				chamber.Set(int(x)+sx, int(y)-sy, '#')
 			}
 		}
 	}
 }
 
 // Check if position is occupied
-func filled(x, y int64) bool {
-	_, ok := chamber[Point{int64(x), int64(y)}]
-	return ok
+func (chamber Chamber) filled(x, y int64) bool {
+	return chamber.Has(int(x), int(y))
+}
+
+// Show the top rows of the chamber, with the walls (for debugging)
+func (chamber Chamber) visualize(rows int) {
+	_, top := chamber.Bounds()
+	lo := grid.Point{X: 1, Y: top.Y - rows + 1}
+	hi := grid.Point{X: 7, Y: top.Y}
+	for _, l := range strings.Split(chamber.RenderIn(lo, hi, grid.Style[byte]{Empty: '.', YUp: true}), "\n") {
+		if len(l) > 0 {
+			fmt.Println("|" + l + "|")
+		}
+	}
 }
@@ -1,3 +0,0 @@
-module day18
-
-go 1.19
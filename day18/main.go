@@ -7,69 +7,100 @@
 //
 // AK, 18 Dec 2022
 
-package main
+package day18
 
 import (
 	"fmt"
-	"strings"
+	"io"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
+func init() {
+	aoc.Register(18, func() aoc.Solver { return &solver{} })
+}
+
 // A point in 3-d space
 type Point struct {
 	x, y, z int
 }
 
-// Maps of points in space, and points explored (global vars)
-var points map[Point]bool
-var explored map[Point]bool
-
-func main() {
+// The parsed input: map of points in space
+type solver struct {
+	points map[Point]bool
+}
 
-	// Read the input file into set of points
-	lines := readLines("sample.txt")
-	lines = readLines("input.txt") // uncomment to use problem input
-	points = map[Point]bool{}      // initialize map of points in space
-	explored = map[Point]bool{}    // initialize map of points explored
+// Read the input file into set of points
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	s.points = map[Point]bool{}
 	for i := 0; i < len(lines); i++ {
-		nums := strings.Split(lines[i], ",")
-		p := Point{atoi(nums[0]), atoi(nums[1]), atoi(nums[2])}
-		points[p] = true
+		nums, err := aocutil.ParseInts(lines[i], ",")
+		if err != nil {
+			return aocutil.AtLine(err, i+1, lines[i])
+		}
+		if len(nums) != 3 {
+			return aocutil.LineErrorf(i+1, "expected x,y,z: %q", lines[i])
+		}
+		p := Point{nums[0], nums[1], nums[2]}
+		s.points[p] = true
 	}
+	return nil
+}
+
+// Part 1: surfaces that don't touch another point (s/b 64, 4628)
+func (s *solver) Part1() (string, error) {
+	part1, _ := s.surfaces()
+	return fmt.Sprint(part1), nil
+}
+
+// Part 2: surfaces that are outside the shape (s/b 58, 2582)
+func (s *solver) Part2() (string, error) {
+	_, part2 := s.surfaces()
+	return fmt.Sprint(part2), nil
+}
+
+// Count the surfaces that don't touch another point (Part 1), and those
+// that are outside the shape (Part 2)
+func (s *solver) surfaces() (int, int) {
+	explored := map[Point]bool{} // initialize map of points explored
 
 	// Part 1: for each point, count up surfaces that don't touch another point
 	// Part 2: only count surfaces that are outside the shape (may include some
 	// face inside of a "tunnel", so can't just look outward from surface)
 	var part1, part2 int
-	for p, _ := range points {
+	for p, _ := range s.points {
 		for _, a := range getAdjacent(p) {
-			if !points[a] { // Part 1: include surface of this point
-				part1++           // if it touches no other
-				if freePoint(a) { // Part 2: only include this point if
+			if !s.points[a] { // Part 1: include surface of this point
+				part1++                       // if it touches no other
+				if s.freePoint(a, explored) { // Part 2: only include this point if
 					part2++ // there is a route from it to "outer space"
 				}
 			}
 		}
 	}
 
-	// Show results
-	fmt.Println("Part 1 (s/b 64, 4268):", part1)
-	fmt.Println("Part 2 (s/b 58, 2582):", part2)
+	return part1, part2
 }
 
 // Is point free in any direction, directly or indirectly. "Free" means
 // that there is a route from this point to outer space, i.e., not enclosed
 // within other points
-func freePoint(p Point) bool {
+func (s *solver) freePoint(p Point, explored map[Point]bool) bool {
 
 	// Not free if point is solid
-	if points[p] {
+	if s.points[p] {
 		return false
 	}
 
 	// If this point is free in any direction, return true
-	if free(p, -1, 0, 0) || free(p, 1, 0, 0) ||
-		free(p, 0, -1, 0) || free(p, 0, 1, 0) ||
-		free(p, 0, 0, -1) || free(p, 0, 0, 1) {
+	if s.free(p, -1, 0, 0) || s.free(p, 1, 0, 0) ||
+		s.free(p, 0, -1, 0) || s.free(p, 0, 1, 0) ||
+		s.free(p, 0, 0, -1) || s.free(p, 0, 0, 1) {
 		return true
 	}
 
@@ -77,7 +108,7 @@ func freePoint(p Point) bool {
 	// this point is free if any of those adjacencies are "free"
 	explored[p] = true
 	for _, a := range getAdjacent(p) {
-		if !explored[a] && freePoint(a) {
+		if !explored[a] && s.freePoint(a, explored) {
 			return true
 		}
 	}
@@ -89,10 +120,10 @@ func freePoint(p Point) bool {
 // Is space free next to given point, in given direction?
 // One of dx/dy/dz must be 1 or -1 to specify direction (others zero).
 // Only looks 100 in any direction, adjust this if necessary.
-func free(p Point, dx, dy, dz int) bool {
+func (s *solver) free(p Point, dx, dy, dz int) bool {
 	for i := 1; i < 100; i++ { // adjust if necessary
 		p1 := Point{p.x + dx*i, p.y + dy*i, p.z + dz*i} // look out in direction
-		if points[p1] {                                 // if that point is occupied, not free in this direction
+		if s.points[p1] {                               // if that point is occupied, not free in this direction
 			return false
 		}
 	}
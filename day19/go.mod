@@ -1,3 +0,0 @@
-module day19
-
-go 1.19
@@ -0,0 +1,120 @@
+// Integer linear programming for Day 19, as an alternative to the search,
+// to check it: a time-indexed model of the robot factory, with a 0/1
+// variable for each kind of robot and each minute, which is 1 if a robot of
+// that kind is started in that minute. Solved with the ilp package.
+//
+//   - At most one robot is started in each minute.
+//   - For each resource and each minute, what the robots started up to
+//     then cost can't be more than what has been collected before it (by
+//     the robots at the start, and those finished in earlier minutes).
+//   - The objective is how much of the target resource (geodes in the
+//     puzzle) there is at the end: a robot for it started in minute t
+//     collects one in each minute after it, less what the robots started
+//     cost of it.
+//
+// AK, Dec 2022
+
+package day19
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"adventofcode2022/ilp"
+)
+
+// Most of a resource that a blueprint can end up with in a number of
+// minutes, by integer linear programming (or the most found so far, if the
+// context is cancelled or its deadline passes)
+func ilpBest(ctx context.Context, bp *Blueprint, target string, minutes int) (int, error) {
+	t := bp.index(target)
+	if t < 0 {
+		return 0, fmt.Errorf("no resource %q", target)
+	}
+	p := factoryModel(bp, t, minutes)
+	sol, err := p.SolveContext(ctx)
+	if sol == nil {
+		return 0, err
+	}
+	return int(math.Round(sol.Value)) + bp.startWith(t)*minutes, err
+}
+
+// Make the model of the factory for a blueprint, from its costs, to end up
+// with the most of resource "target" (less what the robot at the start
+// collects, which is a constant). A robot started in the last minute is no
+// use, so the variables are for minutes 1 to minutes-1, and the variable
+// for robot kind r in minute t is number (t-1)*n + r, for n resources (in
+// order of time, so that branch and bound decides what to do in the
+// earlier minutes first).
+func factoryModel(bp *Blueprint, target, minutes int) *ilp.Problem {
+	if minutes < 2 {
+		return ilp.New(0)
+	}
+	n, steps, cost := len(bp.names), minutes-1, bp.cost
+	p := ilp.New(n * steps)
+	v := func(r, t int) int { return (t-1)*n + r } // (no upper bounds needed, see below)
+
+	// No robots of a kind without a recipe
+	for r := 0; r < n; r++ {
+		if !bp.hasRecipe[r] {
+			for t := 1; t <= steps; t++ {
+				p.Upper[v(r, t)] = 0
+			}
+		}
+	}
+
+	// At most one robot started each minute (which also keeps each
+	// variable to 0 or 1)
+	for t := 1; t <= steps; t++ {
+		row := make([]float64, len(p.Obj))
+		for r := 0; r < n; r++ {
+			row[v(r, t)] = 1
+		}
+		p.Add(row, 1)
+	}
+
+	// Robots started by minute t cost no more than was collected in
+	// minutes 1 to t-1: a robot of that kind started in minute k collects
+	// in minutes k+1 to t-1
+	for i := 0; i < n; i++ {
+		if bp.mostCost(i) == 0 {
+			continue
+		}
+		for t := 1; t <= steps; t++ {
+			row := make([]float64, len(p.Obj))
+			for r := 0; r < n; r++ {
+				for k := 1; k <= t; k++ {
+					row[v(r, k)] += float64(cost[r][i])
+				}
+			}
+			for k := 1; k <= t-2; k++ {
+				row[v(i, k)] -= float64(t - 1 - k)
+			}
+			p.Add(row, float64(bp.startWith(i)*(t-1)))
+		}
+	}
+
+	// No more robots of a kind (other than the target) than any robot
+	// costs of that resource, as only one robot can be started each minute
+	for i := 0; i < n; i++ {
+		if i == target {
+			continue
+		}
+		row := make([]float64, len(p.Obj))
+		for t := 1; t <= steps; t++ {
+			row[v(i, t)] = 1
+		}
+		p.Add(row, math.Max(0, float64(bp.mostCost(i)-bp.startWith(i))))
+	}
+
+	// What each robot for the target collects, less what each robot costs
+	// of it
+	for t := 1; t <= steps; t++ {
+		p.Obj[v(target, t)] += float64(minutes - t)
+		for r := 0; r < n; r++ {
+			p.Obj[v(r, t)] -= float64(cost[r][target])
+		}
+	}
+	return p
+}
@@ -0,0 +1,25 @@
+// Unit tests for integer linear programming on Day 19, checked against
+// the search (for fewer minutes than the puzzle, as the ILP is slow)
+
+package day19
+
+import (
+	"context"
+	"testing"
+)
+
+func TestILP(t *testing.T) {
+	bps := readSample(t)
+	for i := range bps {
+		for _, c := range []struct {
+			target  string
+			minutes int
+		}{{"geode", 1}, {"geode", 12}, {"geode", 19}, {"obsidian", 14}, {"ore", 10}} {
+			want := best(t, &bps[i], c.target, c.minutes)
+			got, err := ilpBest(context.Background(), &bps[i], c.target, c.minutes)
+			if err != nil || got != want {
+				t.Errorf("blueprint %d, %d minutes: ILP gives %d %s, %v, search %d", i+1, c.minutes, got, c.target, err, want)
+			}
+		}
+	}
+}
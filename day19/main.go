@@ -14,23 +14,50 @@
 // repository), got the answer for Part 1 after many hours of execution time.
 // Revisited and wrote as a recursive depth-first search, that stops when a
 // branch fails to achieve the same number of "geodes" as another branch, at
-// the same time in the simulation.  Runs concurrently, takes about .5 seconds
-// for both parts.
+// the same time in the simulation (which pruned too much for the sample).
+// Now a branch-and-bound search that skips ahead to the next robot built
+// (see search.go), which finds the best plan for certain. Runs on a pool of
+// workers, with an optional time limit for each blueprint (see pool.go),
+// takes a few milliseconds for both parts. As a check, it can also be
+// solved by integer linear programming (see ilp.go), e.g., "aoc run 19
+// --opt solver=check", which is much slower.
+//
+// The resources are whatever the recipes mention, rather than just the four
+// in the puzzle, and the one to get the most of can be chosen with "--opt
+// maximize=name". "aoc show 19 plan1" (or plan2) shows the best plan for
+// each blueprint, minute by minute (see plan.go).
 //
 // AK, 19-23 Dec 2022
 
-package main
+package day19
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
+	"time"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
-// A blueprint, set of recipes for creating the different types of robots
+func init() {
+	aoc.Register(19, func() aoc.Solver { return &solver{} })
+}
+
+// A blueprint, set of recipes for creating the different types of robots,
+// and the costs worked out from them: the resources are numbered in the
+// order they are first mentioned
 type Blueprint struct {
-	number  int
-	recipes []Recipe
-	bestAt  map[int]int // keep track of best found at every time step
+	number    int
+	recipes   []Recipe
+	names     []string        // name of each resource
+	cost      [maxRes]amounts // what each kind of robot costs
+	hasRecipe [maxRes]bool    // which kinds of robot can be built
+	start     int             // kind of robot there is one of at the start
 }
 
 // Recipe for making a type of robot, i.e., list of ingredients
@@ -45,184 +72,124 @@ type Ingredient struct {
 	requires int
 }
 
-// Number of minutes (24 for part 1, 32 for part 2)
-var minutes int = 24
-
-func main() {
-
-	// Read blueprints
-	fname := "sample.txt"
-	fname = "input.txt"
-	blueprints := readBlueprints(fname)
-	fmt.Println("Part 1: optimizing", len(blueprints), "blueprints")
-
-	// For Part 1, optimize all 30 blueprints in parallel, sum up the
-	// maximum number of geodes possible multiplied by the blueprint
-	// number
-	ch := make(chan int)
-	for i := 0; i < len(blueprints); i++ {
-		go optimize(&blueprints[i], ch)
-	}
-
-	// Retrieve results and add them up for Part 1
-	part1 := 0
-	for i := 0; i < len(blueprints); i++ {
-		x := <-ch
-		part1 += x
-	}
-	fmt.Println("Part 1:", part1)
+// Format of the input: a blueprint on each line, with a list of recipes,
+// each with a list of ingredients
+var (
+	blueprintLine  = aocutil.MustPattern("Blueprint {int}: {str}")
+	recipeText     = aocutil.MustPattern("Each {word} robot costs {str}.")
+	ingredientText = aocutil.MustPattern("{int} {word}")
+)
 
-	// Part 2
-	fmt.Println("\nPart 2 starting")
-	ch2 := make(chan int)
-	part2 := 1                    // initialize multiplier
-	minutes = 32                  // 32 mins instead of 24!
-	nbp := mn(3, len(blueprints)) // only do up to 3 blueprints
-	for i := 0; i < nbp; i++ {    // start in background
-		go optimize(&blueprints[i], ch2)
-	}
-	for i := 0; i < nbp; i++ { // await results
-		x := <-ch2
-		part2 *= x
-	}
-	fmt.Println("Part 2:", part2)
+// The parsed input: list of blueprints, and how to optimize them
+type solver struct {
+	blueprints []Blueprint
+	cfg        Config
 }
 
-// Find the maximum number of geodes you can produce in 24 minutes, given
-// the cost structure in the blueprint:
-//   - Start with one ore robot
-//   - Each robot produces one unit of its type per minute
-//   - You can build one new robot per time step, if you have the necessary
-//     materials on-hand (defined in the blueprint)
-//   - But the robot is only ready at the end of the time step
-func optimize(bp *Blueprint, ch chan int) {
-
-	// Initialize lists of materials we have, and robots we have
-	fmt.Println("Starting blueprint", bp.number)
-	robots := map[string]int{"ore": 1}
-	materials := map[string]int{}
-
-	// Prepare to keep track of best result at each time, stored in the
-	// blueprint rather than in global variable so we can run in parallel
-	bp.bestAt = map[int]int{}
-
-	// Start the optimization at time 1
-	geodes := optimize1(bp, robots, materials, 1)
-	fmt.Printf("Blueprint %d: %d geodes\n", bp.number, geodes)
-
-	// Send result back to channel
-	if minutes == 24 { // for part 1, multiply geods by blueprint number
-		geodes *= (*bp).number
+// Options: solver=search, ilp (integer linear programming, much slower),
+// or check (both, which must agree), maximize=resource (geode by default),
+// workers=number of blueprints optimized at once, timeout=time allowed for
+// each blueprint (e.g., 10s), and progress=true to show each blueprint
+// starting and ending on standard error
+func (s *solver) SetOption(name, value string) error {
+	switch name {
+	case "solver":
+		if !aocutil.In(value, []string{"search", "ilp", "check"}) {
+			return fmt.Errorf("unknown solver %q (can be search, ilp or check)", value)
+		}
+		s.cfg.Solver = value
+	case "maximize":
+		s.cfg.Target = value
+	case "workers":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid number of workers %q", value)
+		}
+		s.cfg.Workers = n
+	case "timeout":
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q", value)
+		}
+		s.cfg.Timeout = d
+	case "progress":
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid progress %q (can be true or false)", value)
+		}
+		s.cfg.Progress = nil
+		if on {
+			s.cfg.Progress = func(e Event) { fmt.Fprintln(os.Stderr, e) }
+		}
+	default:
+		return fmt.Errorf("unknown option %q", name)
 	}
-	ch <- geodes
+	return nil
 }
 
-// One recursive step of the optimization, returns the number of geodes made
-// to end of simulation. Note that function call changes maps, so need to work
-// on copies for each recursive call.
-func optimize1(bp *Blueprint, robots, materials map[string]int, time int) int {
-
-	// Update materials supply with the robots we already have
-	materials0 := copyMap(materials) // work on a copy
-	for rtype, n := range robots {   // each robot produces one of its kind
-		materials0[rtype] += n // each time step
-	}
-
-	// If out of time, return final number of geodes
-	if time >= minutes {
-		return materials0["geode"]
+// Read the blueprints, one per line
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
 	}
+	s.blueprints, err = readBlueprints(lines)
+	return err
+}
 
-	// Stop here if number of geodes is not the best achieved so far by
-	// this time
-	if materials0["geode"] < bp.bestAt[time] {
-		return 0
-	} else if materials0["geode"] > bp.bestAt[time] {
-		bp.bestAt[time] = materials0["geode"]
+// For Part 1, optimize all 30 blueprints in parallel, sum up the
+// maximum number of geodes possible multiplied by the blueprint
+// number (s/b 33, 1653; the first search gave 1390)
+func (s *solver) Part1() (string, error) {
+	all, err := optimizeAll(context.Background(), s.cfg, s.blueprints, 24)
+	if err != nil {
+		return "", err
 	}
-
-	// Check all the recipes we *could* build, given materials we now have.
-	cb := []Recipe{}
-	grec := -1
-	orec := -1
-	for i, recipe := range bp.recipes {
-		if canBuild(recipe, materials) { // not materials0!
-			if recipe.robotType == "geode" {
-				grec = i
-			} else if recipe.robotType == "obsidian" {
-				orec = i
-			}
-			cb = append(cb, recipe)
-		}
+	part1 := 0
+	for i, geodes := range all {
+		part1 += geodes * s.blueprints[i].number
 	}
+	return fmt.Sprint(part1), nil
+}
 
-	// If we have enough to build a geode, try only that. Otherwise, if we
-	// have enough to build an obsidian, try only that. Otherwise, try the
-	// other robot types we have enough for.
-	if grec >= 0 {
-		cb = []Recipe{bp.recipes[grec]}
-	} else if orec >= 0 {
-		cb = []Recipe{bp.recipes[orec]}
+// Part 2: only the first 3 blueprints, for 32 minutes, multiply
+// the maximum number of geodes (s/b 3472, 4212)
+func (s *solver) Part2() (string, error) {
+	nbp := aocutil.Min([]int{3, len(s.blueprints)}) // only do up to 3 blueprints
+	all, err := optimizeAll(context.Background(), s.cfg, s.blueprints[:nbp], 32)
+	if err != nil {
+		return "", err
 	}
-
-	// If we can't build anything yet, try simulating the next time step without
-	// building anything (necessary early on in the simulation)
-	best := 0 // best result achieve this iteration
-	if len(cb) == 0 {
-		best = optimize1(bp, robots, materials0, time+1)
+	part2 := 1 // initialize multiplier
+	for _, geodes := range all {
+		part2 *= geodes
 	}
-
-	// For each robot type we could build, simulate building it and continuing
-	for _, recipe := range cb {
-
-		// Use up required materials, and add the new robot
-		materials1 := copyMap(materials0) // don't alter original
-		for _, i := range recipe.ingredients {
-			materials1[i.material] -= i.requires
-		}
-
-		// Add the new robot
-		robots1 := copyMap(robots) // don't alter original
-		robots1[recipe.robotType] += 1
-
-		// Try next time step with this configuration
-		geodes := optimize1(bp, robots1, materials1, time+1)
-		if geodes > best {
-			best = geodes
-		}
-	}
-
-	// Return the best number of geodes achieved to the end of the simulation
-	// from any branch tried here
-	return best
+	return fmt.Sprint(part2), nil
 }
 
-// Can we build at least one of this recipe with materials on-hand?
-func canBuild(recipe Recipe, materials map[string]int) bool {
-	for _, i := range recipe.ingredients {
-		if materials[i.material] < i.requires { // redundant
-			return false
-		}
-	}
-	return true
+// Ways of showing the input: the best plan for each blueprint in Part 1,
+// or Part 2 (see plan.go)
+func (s *solver) Formats() []string {
+	return []string{"plan1", "plan2"}
 }
 
-// Make a deep copy of a string->int map
-func copyMap(m map[string]int) map[string]int {
-	m2 := map[string]int{}
-	for k, v := range m {
-		m2[k] = v
+// Show the best plan for each blueprint, minute by minute
+func (s *solver) Show(w io.Writer, format string) error {
+	bps, minutes := s.blueprints, 24
+	if format == "plan2" {
+		bps, minutes = s.blueprints[:aocutil.Min([]int{3, len(s.blueprints)})], 32
 	}
-	return m2
-}
-
-// Minimum of two numbers
-func mn(a, b int) int {
-	if a < b {
-		return a
-	} else {
-		return b
+	for i := range bps {
+		p, err := bestPlan(context.Background(), &bps[i], s.cfg.goal(), minutes)
+		if err != nil {
+			return err
+		}
+		if err := bps[i].writePlan(w, p); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
 	}
+	return nil
 }
 
 // Read blueprints from file and return list of them
@@ -242,27 +209,113 @@ func mn(a, b int) int {
 //	  Recipe{"geode", []Ingredient{
 //	    Ingredient{"ore", 2}, Ingredient{"obs", 7}}},
 //	}
-func readBlueprints(fname string) []Blueprint {
+func readBlueprints(lines []string) ([]Blueprint, error) {
 
 	// Parse each blueprint, one per line
 	blueprints := []Blueprint{}
-	for _, l := range readLines(fname) {
+	for ln, l := range lines {
+		bp := Blueprint{}
+		var costs string
+		if err := blueprintLine.Match(l, &bp.number, &costs); err != nil {
+			return nil, aocutil.AtLine(err, ln+1, l)
+		}
 
-		parts := strings.Split(l, ":")
-		bp := Blueprint{number: len(blueprints) + 1}
-		costs := strings.Split(parts[1], ".")
-		for i := 0; i < len(costs)-1; i++ {
-			words := strings.Split(strings.TrimSpace(costs[i]), " ")
-			rec := Recipe{robotType: words[1]}
-			ing := Ingredient{words[5], atoi(words[4])}
-			rec.ingredients = append(rec.ingredients, ing)
-			if len(words) > 6 {
-				ing = Ingredient{words[8], atoi(words[7])}
-				rec.ingredients = append(rec.ingredients, ing)
+		// Each recipe ends with a period, e.g., "Each obsidian robot
+		// costs 3 ore and 14 clay." (keep track of where each part of
+		// the line starts, for errors)
+		at := len(l) - len(costs)
+		for _, cost := range strings.SplitAfter(costs, ".") {
+			if len(strings.TrimSpace(cost)) == 0 {
+				continue
+			}
+			var rec Recipe
+			var ingredients string
+			if err := recipeText.Match(cost, &rec.robotType, &ingredients); err != nil {
+				return nil, aocutil.AtLine(aocutil.AtCol(err, at), ln+1, l)
+			}
+			iat := at + strings.Index(cost, ingredients)
+			for _, ing := range strings.Split(ingredients, " and ") { // e.g., "3 ore and 14 clay"
+				var in Ingredient
+				if err := ingredientText.Match(ing, &in.requires, &in.material); err != nil {
+					return nil, aocutil.AtLine(aocutil.AtCol(err, iat), ln+1, l)
+				}
+				rec.ingredients = append(rec.ingredients, in)
+				iat += len(ing) + len(" and ")
 			}
 			bp.recipes = append(bp.recipes, rec)
+			at += len(cost)
+		}
+		if err := bp.findCosts(); err != nil {
+			return nil, aocutil.AtLine(err, ln+1, l)
 		}
 		blueprints = append(blueprints, bp)
 	}
-	return blueprints
+	return blueprints, nil
+}
+
+// Fill in the resources and the cost of each kind of robot from the
+// recipes, checking that there is only one recipe for each kind. There is
+// one robot at the start, of the kind in the first recipe (ore in the
+// puzzle).
+func (bp *Blueprint) findCosts() error {
+	for _, rec := range bp.recipes {
+		r, err := bp.add(rec.robotType)
+		if err != nil {
+			return err
+		}
+		if bp.hasRecipe[r] {
+			return fmt.Errorf("two recipes for %s robots", rec.robotType)
+		}
+		bp.hasRecipe[r] = true
+		for _, in := range rec.ingredients {
+			i, err := bp.add(in.material)
+			if err != nil {
+				return err
+			}
+			bp.cost[r][i] += in.requires
+		}
+	}
+	if len(bp.recipes) == 0 {
+		return fmt.Errorf("no recipes")
+	}
+	bp.start = bp.index(bp.recipes[0].robotType)
+	return nil
+}
+
+// How many robots of a kind there are at the start
+func (bp *Blueprint) startWith(r int) int {
+	return aocutil.IfElse(r == bp.start, 1, 0)
+}
+
+// The most of a resource that any robot costs
+func (bp *Blueprint) mostCost(i int) int {
+	most := 0
+	for _, c := range bp.cost {
+		if c[i] > most {
+			most = c[i]
+		}
+	}
+	return most
+}
+
+// The number of a resource, by name, -1 if unknown
+func (bp *Blueprint) index(name string) int {
+	for i, r := range bp.names {
+		if r == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// The number of a resource, adding it if it is new
+func (bp *Blueprint) add(name string) (int, error) {
+	if i := bp.index(name); i >= 0 {
+		return i, nil
+	}
+	if len(bp.names) == maxRes {
+		return 0, fmt.Errorf("more than %d resources", maxRes)
+	}
+	bp.names = append(bp.names, name)
+	return len(bp.names) - 1, nil
 }
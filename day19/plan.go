@@ -0,0 +1,103 @@
+// Plans for Day 19: the robots built in each minute, and the robots and
+// stock of each resource at the end of it, to check an answer against the
+// walkthrough in the puzzle.
+//
+// AK, Dec 2022
+
+package day19
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"adventofcode2022/aocutil"
+)
+
+// The robots and stock of each resource at the end of a minute, and the
+// robot started in it (-1 if none)
+type Inventory struct {
+	Minute int
+	Built  int
+	Robots amounts
+	Stock  amounts
+}
+
+// Follow a plan minute by minute from the start: a robot is paid for at the
+// start of the minute, then all the robots collect, and the new robot is
+// ready at the end. Returns an error if a robot is built before there is
+// enough to pay for it, or two in the same minute.
+func (bp *Blueprint) simulate(p *Plan) ([]Inventory, error) {
+	var robots, stock amounts
+	robots[bp.start] = 1
+	inv, next := []Inventory{}, 0
+	for m := 1; m <= p.Minutes; m++ {
+		built := -1
+		if next < len(p.Builds) && p.Builds[next].Minute == m {
+			built = p.Builds[next].Robot
+			if !covers(stock, bp.cost[built]) {
+				return nil, fmt.Errorf("minute %d: not enough to build a %s robot", m, bp.names[built])
+			}
+			for i, c := range bp.cost[built] {
+				stock[i] -= c
+			}
+			next++
+		}
+		for i, n := range robots {
+			stock[i] += n
+		}
+		if built >= 0 {
+			robots[built]++
+		}
+		inv = append(inv, Inventory{m, built, robots, stock})
+	}
+	if next < len(p.Builds) {
+		return nil, fmt.Errorf("robot built in minute %d, out of order or too late", p.Builds[next].Minute)
+	}
+	return inv, nil
+}
+
+// Write a plan as a table, with the robot built in each minute, and the
+// robots and stock of each resource at the end of it, e.g.,
+//
+//	Blueprint 1: 9 geode in 24 minutes
+//	Minute  Build       ore   clay  obsidian  geode
+//	     1              1/1    0/0       0/0    0/0
+//	     3  clay        1/1    1/0       0/0    0/0
+func (bp *Blueprint) writePlan(w io.Writer, p *Plan) error {
+	inv, err := bp.simulate(p)
+	if err != nil {
+		return fmt.Errorf("blueprint %d: %w", bp.number, err)
+	}
+	fmt.Fprintf(w, "Blueprint %d: %d %s in %d minutes\n", bp.number, p.Value, bp.names[p.Target], p.Minutes)
+
+	// Columns wide enough for the names of the resources and robots
+	build := len("Build")
+	for _, n := range bp.names {
+		build = aocutil.Max([]int{build, len(n)})
+	}
+	widths := make([]int, len(bp.names))
+	for i, n := range bp.names {
+		widths[i] = aocutil.Max([]int{len(n), 5})
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Minute  %-*s", build, "Build")
+	for i, n := range bp.names {
+		fmt.Fprintf(&b, "  %*s", widths[i], n)
+	}
+	fmt.Fprintln(w, b.String())
+
+	for _, in := range inv {
+		b.Reset()
+		name := ""
+		if in.Built >= 0 {
+			name = bp.names[in.Built]
+		}
+		fmt.Fprintf(&b, "%6d  %-*s", in.Minute, build, name)
+		for i := range bp.names {
+			fmt.Fprintf(&b, "  %*s", widths[i], fmt.Sprintf("%d/%d", in.Robots[i], in.Stock[i]))
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// Optimizing many blueprints for Day 19: a pool of workers, each taking the
+// next blueprint to optimize, with a deadline for each blueprint. A
+// blueprint that takes too long is stopped and reported (with the best
+// found so far), and the others carry on. Progress is reported with an
+// event as each blueprint starts and ends.
+//
+// AK, Dec 2022
+
+package day19
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// How to optimize the blueprints, for one run
+type Config struct {
+	Solver   string        // "search" (the default), "ilp", or "check" for both
+	Target   string        // resource to get the most of, geode if not set
+	Workers  int           // blueprints optimized at once, one per CPU if 0
+	Timeout  time.Duration // time allowed for each blueprint, no limit if 0
+	Progress func(Event)   // called for each event, one at a time, if set
+}
+
+// What happened to a blueprint
+type EventKind int
+
+const (
+	Started EventKind = iota // a worker started on it
+	Done                     // optimized
+	Stopped                  // cancelled, or ran out of time
+	Failed                   // an error
+)
+
+// Names of the kinds of event
+func (k EventKind) String() string {
+	return [...]string{"started", "done", "stopped", "failed"}[k]
+}
+
+// Progress of one blueprint
+type Event struct {
+	Blueprint int // blueprint number
+	Kind      EventKind
+	Best      int           // most of the target (found so far, if stopped)
+	Elapsed   time.Duration // time taken, when it ends
+	Err       error         // why it stopped or failed
+}
+
+// Write an event as one line, e.g., "blueprint 2 done in 5ms: 12"
+func (e Event) String() string {
+	s := fmt.Sprintf("blueprint %d %v", e.Blueprint, e.Kind)
+	if e.Kind != Started {
+		s += fmt.Sprintf(" in %v: %d", e.Elapsed.Round(time.Millisecond), e.Best)
+	}
+	if e.Err != nil {
+		s += fmt.Sprintf(" (%v)", e.Err)
+	}
+	return s
+}
+
+// The resource to get the most of
+func (c *Config) goal() string {
+	if c.Target == "" {
+		return "geode"
+	}
+	return c.Target
+}
+
+// A function that finds the most of a resource a blueprint can end up
+// with in a number of minutes, or the most found so far with the context's
+// error if it is cancelled
+type optimizer func(ctx context.Context, bp *Blueprint, target string, minutes int) (int, error)
+
+// The optimizer chosen by the configuration
+func (c *Config) optimizer() optimizer {
+	switch c.Solver {
+	case "ilp":
+		return ilpBest
+	case "check":
+		return checkBest
+	}
+	return searchBest
+}
+
+// Optimize with the search
+func searchBest(ctx context.Context, bp *Blueprint, target string, minutes int) (int, error) {
+	p, err := bestPlan(ctx, bp, target, minutes)
+	if p == nil {
+		return 0, err
+	}
+	return p.Value, err
+}
+
+// Optimize with both the search and integer linear programming, which
+// must agree
+func checkBest(ctx context.Context, bp *Blueprint, target string, minutes int) (int, error) {
+	best, err := searchBest(ctx, bp, target, minutes)
+	if err != nil {
+		return best, err
+	}
+	b, err := ilpBest(ctx, bp, target, minutes)
+	if err != nil {
+		return best, err
+	}
+	if b != best {
+		return 0, fmt.Errorf("search finds %d %s, ILP %d", best, target, b)
+	}
+	return best, nil
+}
+
+// Optimize blueprints for the given number of minutes with a pool of
+// workers, and return the most of the target resource for each. If any
+// blueprint is stopped or fails, the others are still optimized, and the
+// error lists all that didn't finish (wrapping the first one's error).
+func optimizeAll(ctx context.Context, cfg Config, blueprints []Blueprint, minutes int) ([]int, error) {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	opt, target := cfg.optimizer(), cfg.goal()
+
+	// Start the workers, taking blueprints from a channel, and sending
+	// events back
+	type event struct {
+		i int // index of the blueprint
+		Event
+	}
+	jobs := make(chan int)
+	events := make(chan event)
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				bp := &blueprints[i]
+				events <- event{i, Event{Blueprint: bp.number, Kind: Started}}
+				bctx, cancel := ctx, context.CancelFunc(func() {})
+				if cfg.Timeout > 0 {
+					bctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+				}
+				t0 := time.Now()
+				best, err := opt(bctx, bp, target, minutes)
+				e := Event{Blueprint: bp.number, Kind: Done, Best: best, Elapsed: time.Since(t0), Err: err}
+				if err != nil {
+					e.Kind = Failed
+					if bctx.Err() != nil {
+						e.Kind = Stopped
+					}
+				}
+				cancel()
+				events <- event{i, e}
+			}
+		}()
+	}
+	go func() {
+		for i := range blueprints {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	// Collect the results, passing on the events, until all the blueprints
+	// have ended
+	best := make([]int, len(blueprints))
+	var bad []Event
+	for ended := 0; ended < len(blueprints); {
+		e := <-events
+		if cfg.Progress != nil {
+			cfg.Progress(e.Event)
+		}
+		if e.Kind == Started {
+			continue
+		}
+		ended++
+		best[e.i] = e.Best
+		if e.Kind != Done {
+			bad = append(bad, e.Event)
+		}
+	}
+	if len(bad) == 0 {
+		return best, nil
+	}
+	sort.Slice(bad, func(i, j int) bool { return bad[i].Blueprint < bad[j].Blueprint })
+	return best, &RunError{bad}
+}
+
+// The blueprints that were stopped or failed in a run
+type RunError struct {
+	Events []Event // how each one ended, in order of blueprint number
+}
+
+// All the blueprints, as their events, separated by semicolons
+func (e *RunError) Error() string {
+	msgs := []string{}
+	for _, ev := range e.Events {
+		msgs = append(msgs, ev.String())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// The error of the first blueprint, so errors.Is finds, e.g.,
+// context.DeadlineExceeded
+func (e *RunError) Unwrap() error {
+	return e.Events[0].Err
+}
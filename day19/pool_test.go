@@ -0,0 +1,54 @@
+// Unit tests for the Day 19 worker pool
+
+package day19
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"adventofcode2022/aocutil"
+)
+
+func TestOptimizeAll(t *testing.T) {
+	bps := readSample(t)
+
+	// One worker at a time, with time to spare: a start and an end for
+	// each blueprint, one after the other
+	var kinds []EventKind
+	cfg := Config{Workers: 1, Timeout: time.Minute, Progress: func(e Event) { kinds = append(kinds, e.Kind) }}
+	best, err := optimizeAll(context.Background(), cfg, bps, 24)
+	if err != nil || len(best) != 2 || best[0] != 9 || best[1] != 12 {
+		t.Errorf("got %v, %v, want [9 12]", best, err)
+	}
+	if want := []EventKind{Started, Done, Started, Done}; !aocutil.Same(kinds, want) {
+		t.Errorf("events %v, want %v", kinds, want)
+	}
+
+	// Cancelled before it starts, the ILP stops at once, so both blueprints
+	// are stopped, and reported together
+	var stopped int
+	cfg = Config{Solver: "ilp", Workers: 2, Timeout: time.Minute,
+		Progress: func(e Event) {
+			if e.Kind == Stopped {
+				stopped++
+			}
+		}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = optimizeAll(ctx, cfg, bps, 24)
+	var re *RunError
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &re) || len(re.Events) != 2 || stopped != 2 {
+		t.Errorf("got %v with %d stopped, want both stopped", err, stopped)
+	}
+
+	// Errors are reported for each blueprint, with its number
+	cfg = Config{Target: "silver"}
+	_, err = optimizeAll(context.Background(), cfg, bps, 12)
+	if !errors.As(err, &re) || len(re.Events) != 2 || re.Events[1].Kind != Failed ||
+		!strings.Contains(err.Error(), `; blueprint 2 failed in `) || !strings.HasSuffix(err.Error(), `: 0 (no resource "silver")`) {
+		t.Errorf("got %v, want both failed", err)
+	}
+}
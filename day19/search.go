@@ -0,0 +1,205 @@
+// Branch-and-bound search for Day 19, which finds the most of a resource
+// (geodes, in the puzzle) that a blueprint can end up with, and is sure to
+// (the first version forced building a geode or obsidian robot whenever it
+// could, which misses the best plan for the sample). The resources are
+// whatever the blueprint's recipes mention, up to maxRes of them.
+//
+// The state is kept in fixed-size arrays (robots and stock of each
+// resource), and rather than going one minute at a time, each step of the
+// search decides which robot to build next, and skips ahead to when there
+// is enough to build it. There is no point having more robots of a kind
+// than the most of that resource any robot costs (except for the resource
+// wanted), since only one robot can be built each minute. A branch is cut
+// off if even an optimistic upper bound on what it could end up with is no
+// better than the best found so far.
+//
+// AK, Dec 2022
+
+package day19
+
+import (
+	"context"
+	"fmt"
+
+	"adventofcode2022/aocutil"
+)
+
+// Most kinds of resource a blueprint can have
+const maxRes = 8
+
+// An amount of each resource, or a number of robots of each kind
+type amounts [maxRes]int
+
+// One robot in a plan, started in a minute (and ready at the end of it)
+type Build struct {
+	Minute int
+	Robot  int // kind of robot, the resource it collects
+}
+
+// A plan for a blueprint: the robots to build, and how much of the target
+// resource there is at the end
+type Plan struct {
+	Minutes int
+	Target  int
+	Value   int
+	Builds  []Build
+}
+
+// The state of the search for one blueprint
+type search struct {
+	bp      *Blueprint
+	n       int             // number of resources
+	cost    [maxRes]amounts // what each kind of robot costs
+	most    amounts         // most robots of each kind worth having
+	target  int             // resource to end up with the most of
+	minutes int
+	order   []int   // kinds of robot to try, in order
+	best    int     // most of the target found so far
+	plan    []Build // how to get that
+	builds  []Build // robots built on the current branch
+	ctx     context.Context
+	nodes   int   // steps of the search so far
+	err     error // why the search was stopped, if it was
+}
+
+// How many steps of the search between checks for cancellation
+const checkEvery = 1 << 12
+
+// Find the plan that ends up with the most of a resource with a blueprint
+// in a number of minutes, starting with one robot of the first kind in the
+// recipes. If the context is cancelled or its deadline passes, returns the
+// best plan found so far, with the context's error.
+func bestPlan(ctx context.Context, bp *Blueprint, target string, minutes int) (*Plan, error) {
+	t := bp.index(target)
+	if t < 0 {
+		return nil, fmt.Errorf("no resource %q", target)
+	}
+	sr := &search{bp: bp, n: len(bp.names), cost: bp.cost, target: t, minutes: minutes, ctx: ctx}
+
+	// Robots worth having: enough to pay for any robot each minute, and
+	// as many as possible of the target
+	for r := 0; r < sr.n; r++ {
+		sr.most[r] = aocutil.IfElse(r == t, minutes, bp.mostCost(r))
+		if !bp.hasRecipe[r] {
+			sr.most[r] = 0
+		}
+	}
+
+	// Try the robots for the target first, to find good plans early, then
+	// the others from the last kind to the first
+	sr.order = []int{t}
+	for r := sr.n - 1; r >= 0; r-- {
+		if r != t {
+			sr.order = append(sr.order, r)
+		}
+	}
+
+	robots := amounts{}
+	robots[bp.start] = 1
+	sr.best = -1
+	sr.visit(robots, amounts{}, minutes)
+	return &Plan{Minutes: minutes, Target: t, Value: sr.best, Builds: sr.plan}, sr.err
+}
+
+// One step of the search: with these robots and this stock, and some
+// minutes left, see what building nothing more would give, then try
+// building each kind of robot next
+func (sr *search) visit(robots, stock amounts, left int) {
+	if v := stock[sr.target] + robots[sr.target]*left; v > sr.best {
+		sr.best = v
+		sr.plan = append([]Build{}, sr.builds...)
+	}
+	if sr.nodes++; sr.nodes%checkEvery == 0 && sr.err == nil {
+		sr.err = sr.ctx.Err()
+	}
+	if sr.err != nil || sr.bound(robots, stock, left) <= sr.best {
+		return
+	}
+
+	for _, r := range sr.order {
+		if robots[r] >= sr.most[r] {
+			continue
+		}
+
+		// Minutes to wait until there is enough of everything this robot
+		// costs (never, if nothing collects one of them yet)
+		wait, possible := 0, true
+		for i := 0; i < sr.n; i++ {
+			need := sr.cost[r][i] - stock[i]
+			if need <= 0 {
+				continue
+			}
+			if robots[i] == 0 {
+				possible = false
+				break
+			}
+			if w := (need + robots[i] - 1) / robots[i]; w > wait {
+				wait = w
+			}
+		}
+
+		// Then a minute to build it, and it is only any use if there is
+		// time left after that
+		t := left - wait - 1
+		if !possible || t <= 0 {
+			continue
+		}
+		next := stock
+		for i := 0; i < sr.n; i++ {
+			next[i] += robots[i]*(wait+1) - sr.cost[r][i]
+		}
+		more := robots
+		more[r]++
+		sr.builds = append(sr.builds, Build{sr.minutes - left + wait + 1, r})
+		sr.visit(more, next, t)
+		sr.builds = sr.builds[:len(sr.builds)-1]
+	}
+}
+
+// Upper bound on how much of the target there could be at the end, as if
+// a robot of each kind could be built every minute, each kind paying from
+// its own copy of the stock (so building one kind never holds up another),
+// and none of the target were ever spent. Building each kind as soon as its
+// copy of the stock allows gives at least as many robots of every kind at
+// every minute as any real plan, so no real plan can do better.
+func (sr *search) bound(robots, stock amounts, left int) int {
+	var copies [maxRes]amounts // each kind's own copy of the stock
+	for r := 0; r < sr.n; r++ {
+		copies[r] = stock
+	}
+	total := stock[sr.target]
+	for m := 0; m < left; m++ {
+		var started [maxRes]bool
+		for r := 0; r < sr.n; r++ {
+			if !sr.bp.hasRecipe[r] || !covers(copies[r], sr.cost[r]) {
+				continue
+			}
+			for i := 0; i < sr.n; i++ {
+				copies[r][i] -= sr.cost[r][i]
+			}
+			started[r] = true
+		}
+		for r := 0; r < sr.n; r++ {
+			for i := 0; i < sr.n; i++ {
+				copies[r][i] += robots[i]
+			}
+		}
+		total += robots[sr.target]
+		for r := 0; r < sr.n; r++ {
+			if started[r] {
+				robots[r]++
+			}
+		}
+	}
+	return total
+}
+
+// Is there enough stock to pay a cost?
+func covers(stock, cost amounts) bool {
+	for i, c := range cost {
+		if stock[i] < c {
+			return false
+		}
+	}
+	return true
+}
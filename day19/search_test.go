@@ -0,0 +1,96 @@
+// Unit tests for the Day 19 search
+
+package day19
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Read the sample blueprints
+func readSample(t *testing.T) []Blueprint {
+	data, err := os.ReadFile("sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bps, err := readBlueprints(strings.Split(strings.TrimSpace(string(data)), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bps
+}
+
+// Most of a resource a blueprint can end up with, checking that the plan
+// can be followed and gets there
+func best(t *testing.T, bp *Blueprint, target string, minutes int) int {
+	p, err := bestPlan(context.Background(), bp, target, minutes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv, err := bp.simulate(p)
+	if err != nil {
+		t.Fatalf("blueprint %d, %d minutes: %v", bp.number, minutes, err)
+	}
+	if got := inv[len(inv)-1].Stock[p.Target]; minutes > 0 && got != p.Value {
+		t.Errorf("blueprint %d, %d minutes: plan ends with %d %s, not %d", bp.number, minutes, got, target, p.Value)
+	}
+	return p.Value
+}
+
+func TestMaxGeodes(t *testing.T) {
+	bps := readSample(t)
+	for _, c := range []struct{ bp, minutes, want int }{
+		{0, 24, 9}, {1, 24, 12}, {0, 32, 56}, {1, 32, 62}, {0, 10, 0},
+	} {
+		if got := best(t, &bps[c.bp], "geode", c.minutes); got != c.want {
+			t.Errorf("blueprint %d, %d minutes: got %d, want %d", c.bp+1, c.minutes, got, c.want)
+		}
+	}
+}
+
+// Other targets, and other resources
+func TestTargets(t *testing.T) {
+	bps := readSample(t)
+	if got := best(t, &bps[0], "obsidian", 24); got <= 0 {
+		t.Errorf("blueprint 1: got %d obsidian", got)
+	}
+
+	// Ore only: a second robot started in minute 3 would be paid off only
+	// by the end of minute 5, so in 4 minutes it's best to build nothing
+	bp, err := readBlueprints([]string{"Blueprint 7: Each ore robot costs 2 ore."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := best(t, &bp[0], "ore", 4); got != 4 {
+		t.Errorf("ore only, 4 minutes: got %d, want 4", got)
+	}
+
+	// Made-up resources, with one no recipe uses
+	bp, err = readBlueprints([]string{"Blueprint 2: Each wood robot costs 2 wood. Each stone robot costs 3 wood. Each gold robot costs 2 wood and 4 stone. Each gem robot costs 9 dust."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := best(t, &bp[0], "gem", 20); got != 0 {
+		t.Errorf("gems without dust: got %d, want 0", got)
+	}
+	if got, want := best(t, &bp[0], "gold", 20), best(t, &bp[0], "stone", 20); got <= 0 || want <= 0 {
+		t.Errorf("got %d gold, %d stone", got, want)
+	}
+	if _, err := bestPlan(context.Background(), &bp[0], "silver", 20); err == nil {
+		t.Errorf("no error for silver")
+	}
+}
+
+func TestBadBlueprints(t *testing.T) {
+	for _, c := range []struct{ line, want string }{
+		{"Blueprint 1: Each ore robot costs 4 ore. Each ore robot costs 2 ore.", "two recipes"},
+		{"Blueprint 1: Each a robot costs 1 b. Each c robot costs 1 d. Each e robot costs 1 f. Each g robot costs 1 h. Each i robot costs 1 j.", "more than 8 resources"},
+	} {
+		_, err := readBlueprints([]string{c.line})
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: got %v, want %s", c.line, err, c.want)
+		}
+	}
+}
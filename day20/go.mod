@@ -1,3 +0,0 @@
-module day20
-
-go 1.19
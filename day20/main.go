@@ -9,138 +9,137 @@
 // of a value. Also, iterations in Part 2 are infeasible with large multiplier
 // as well as 10 iterations.
 //
+// First version moved each number one place at a time, searching the list
+// for it every step, which took many seconds for the input. Now the list is
+// a ring (see the ring package, an implicit treap), which moves a number
+// straight to its new place in logarithmic time, and finds where a number
+// is from its element, which stands for its original position, so
+// duplicates are no problem.
+// The decryption key and number of rounds for Part 2 can be set with, e.g.,
+// "aoc run 20 --opt key=1 --opt rounds=3".
+//
 // AK, 20 Dec 2022
 
-package main
+package day20
 
 import (
 	"fmt"
+	"io"
+	"strconv"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/num"
+	"adventofcode2022/ring"
 )
 
-// Number, with its original position (to get around duplicates)
-type Number struct {
-	pos   int
-	value int64
+func init() {
+	aoc.Register(20, func() aoc.Solver { return &solver{key: 811589153, rounds: 10} })
 }
 
-func main() {
-
-	var part2 bool
-	part2 = true // uncomment this line to run part 2
+// The parsed input: list of numbers, and the decryption key and number of
+// rounds of mixing for Part 2
+type solver struct {
+	values []int64
+	key    int64
+	rounds int
+}
 
-	// Read list of numbers from input file
-	fname := "sample.txt"
-	fname = "input.txt"    // uncomment this line to use problem input
-	var seq, nums []Number //  original and reordered lists
-	for i, l := range readLines(fname) {
-		n := Number{i, atoi64(l)}
-		if part2 {
-			n.value *= 811589153
+// Options for Part 2: key=decryption key (811589153 by default), and
+// rounds=number of times to mix (10 by default)
+func (s *solver) SetOption(name, value string) error {
+	switch name {
+	case "key":
+		k, err := aocutil.ParseInt64(value)
+		if err != nil {
+			return fmt.Errorf("invalid key %q", value)
 		}
-		nums = append(nums, n)
-		seq = append(seq, n)
-	}
-	fmt.Println(len(seq), "numbers")
-
-	// Process each number in original sequence, 10 times in part 2
-	iterations := ifElse(part2, 10, 1)
-	for iter := 0; iter < iterations; iter++ {
-		fmt.Println("Iteration", iter)
-		for _, n := range nums {
-			seq = move(seq, n)
+		s.key = k
+	case "rounds":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid number of rounds %q", value)
 		}
+		s.rounds = n
+	default:
+		return fmt.Errorf("unknown option %q", name)
 	}
-
-	// Get answer: sum of numbers at positions 1000, 2000, 3000
-	zero := findValue(Number{-1, 0}, seq)
-	n1000 := seq[(zero+1000)%len(seq)].value
-	n2000 := seq[(zero+2000)%len(seq)].value
-	n3000 := seq[(zero+3000)%len(seq)].value
-	ans := n1000 + n2000 + n3000
-	fmt.Printf("Answer (s/b 3, 11703 for Part 1, 1623178306 for Part 2): %d\n", ans)
+	return nil
 }
 
-// Move given number x by same number of positions (negative for left)
-func move(seq []Number, x Number) []Number {
-
-	// Number of moves to make: take remainder to avoid too many iterations
-	// (not sure why you need -1, but it works)
-	n := abs(x.value) % int64(len(seq)-1)
-
-	// Move the number forward or backward in the list n times
-	var i int64
-	for i = 0; i < n; i++ {
-		if x.value > 0 {
-			seq = moveRight1(seq, x)
-		} else if x.value < 0 {
-			seq = moveLeft1(seq, x)
+// Read list of numbers from input file
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	s.values = nil
+	for i, l := range lines {
+		n, err := aocutil.ParseInt64(l)
+		if err != nil {
+			return aocutil.AtLine(err, i+1, l)
 		}
+		s.values = append(s.values, n)
 	}
-	return seq
-}
-
-// Move given element one position to the right
-func moveRight1(seq []Number, x Number) []Number {
-
-	// Move element to beginning of list if at end
-	i := findKey(x, seq) // position of the element we want to move
-	if i == len(seq)-1 {
-		seq = shiftRight(seq)
-		i = 0
+	if len(s.values) < 2 {
+		return fmt.Errorf("need at least two numbers, got %d", len(s.values))
 	}
-
-	// Swap the element with the one to its right
-	tmp := seq[i+1]
-	seq[i+1] = seq[i] // = x
-	seq[i] = tmp
-	return seq
+	return nil
 }
 
-// Move given element one position to the left
-func moveLeft1(seq []Number, x Number) []Number {
-
-	// Move element to end if at beginning of list
-	i := findKey(x, seq) // position of the element we want to move
-	if i == 0 {
-		seq = shiftLeft(seq)
-		i = len(seq) - 1
-	}
-
-	// Swap the element with the one to its left
-	tmp := seq[i-1]
-	seq[i-1] = seq[i] // = x
-	seq[i] = tmp
-	return seq
+// Part 1: mix the numbers once (s/b 3, 11073)
+func (s *solver) Part1() (string, error) {
+	ans, err := mix(s.values, 1, 1)
+	return fmt.Sprint(ans), err
 }
 
-// Shift a list one position left, move first element to end
-func shiftLeft(l []Number) []Number {
-	return append(l[1:], l[0])
+// Part 2: multiply by the decryption key, and mix 10 times
+// (s/b 1623178306, 11102539613040)
+func (s *solver) Part2() (string, error) {
+	ans, err := mix(s.values, s.key, s.rounds)
+	return fmt.Sprint(ans), err
 }
 
-// Shift a list one position right, last element goes to beginning
-func shiftRight(l []Number) []Number {
-	return append([]Number{l[len(l)-1]}, l[:len(l)-1]...)
-}
+// Multiply the numbers by a key, mix them a number of times, and return
+// the sum of the numbers 1000, 2000 and 3000 places after the zero (an
+// error if there is no zero, or the numbers get too big)
+func mix(values []int64, key int64, rounds int) (int64, error) {
+
+	// Make the list, multiplied by the key, and find the zero
+	var a num.Int64
+	nums := make([]int64, len(values))
+	zero := -1
+	for i, v := range values {
+		n, err := a.Mul(v, key)
+		if err != nil {
+			return 0, err
+		}
+		nums[i] = n
+		if v == 0 {
+			zero = i
+		}
+	}
+	if zero < 0 {
+		return 0, fmt.Errorf("no zero in list")
+	}
 
-// Index of given element in list, search by position (key), not value
-func findKey(n Number, l []Number) int {
-	for i := 0; i < len(l); i++ {
-		if l[i].pos == n.pos {
-			return i
+	// Move each number in the original order, by its value, each round
+	// (its element in the ring stands for its original position)
+	seq := ring.New(nums...)
+	elems := seq.Elems()
+	for r := 0; r < rounds; r++ {
+		for _, e := range elems {
+			seq.Move(e, int(e.Value))
 		}
 	}
-	fmt.Println("Warning: key", n, "not found!")
-	return -1
-}
 
-// Find by value
-func findValue(n Number, l []Number) int {
-	for i := 0; i < len(l); i++ {
-		if l[i].value == n.value {
-			return i
+	// Get answer: sum of numbers at positions 1000, 2000, 3000 after zero
+	var sum int64
+	for _, k := range []int{1000, 2000, 3000} {
+		var err error
+		if sum, err = a.Add(sum, seq.Offset(elems[zero], k).Value); err != nil {
+			return 0, err
 		}
 	}
-	fmt.Println("Warning: value", n, "not found!")
-	return -1
+	return sum, nil
 }
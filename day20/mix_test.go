@@ -0,0 +1,53 @@
+// Unit tests for mixing the Day 20 numbers
+
+package day20
+
+import (
+	"strings"
+	"testing"
+
+	"adventofcode2022/aoc"
+)
+
+func TestMix(t *testing.T) {
+	sample := []int64{1, 2, -3, 3, -2, 0, 4}
+	for _, c := range []struct {
+		key    int64
+		rounds int
+		want   int64
+	}{{1, 1, 3}, {811589153, 10, 1623178306}, {1, 0, -2 + 3 - 3}} {
+		if got, err := mix(sample, c.key, c.rounds); err != nil || got != c.want {
+			t.Errorf("key %d, %d rounds: got %d, %v, want %d", c.key, c.rounds, got, err, c.want)
+		}
+	}
+
+	// Too big for int64 with the key
+	if _, err := mix([]int64{0, 1 << 40}, 1<<30, 1); err == nil {
+		t.Errorf("no overflow error")
+	}
+}
+
+// A key or number of rounds of 0 is used as given, not as the default
+func TestOptions(t *testing.T) {
+	s := aocSolver(t)
+	for _, c := range []struct{ name, value, want string }{
+		{"rounds", "0", "-1623178306"}, {"key", "0", "0"},
+	} {
+		if err := s.SetOption(c.name, c.value); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := s.Part2(); err != nil || got != c.want {
+			t.Errorf("%s=%s: got %s, %v, want %s", c.name, c.value, got, err, c.want)
+		}
+	}
+}
+
+// A solver made as the aoc command makes it, with the sample
+func aocSolver(t *testing.T) *solver {
+	a, _ := aoc.New(20)
+	s := a.(*solver)
+	if err := s.Parse(strings.NewReader("1\n2\n-3\n3\n-2\n0\n4\n")); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+// Expressions for Day 21: the monkeys turned into a formula in the unknown
+// "humn", with every part that does not depend on it worked out to a number
+// (an exact fraction, so that nothing is lost in divisions), and operations
+// that make no difference (adding 0, multiplying by 1) left out. A monkey
+// used by several others is only worked out once, and the formula can be
+// shown as text (e.g., ((4 + (2 * (humn - 3))) / 4) = 150) or as a Graphviz
+// graph.
+//
+// AK, Dec 2022
+
+package day21
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// An expression: a number, the unknown (humn), or an operation on two
+// expressions, at least one of which contains the unknown
+type expr struct {
+	op       string   // "" for a number, "x" for the unknown, or + - * /
+	num      *big.Rat // the value of a number
+	lhs, rhs *expr    // the operands of an operation
+}
+
+// The two sides of the equation for root, with humn as the unknown
+func (monkeys Monkeys) equation() (*expr, *expr, error) {
+	root := monkeys["root"]
+	if isNumber(root) {
+		return nil, nil, fmt.Errorf("root has no formula")
+	}
+	memo := map[string]*expr{} // shared by both sides
+	lhs, err := monkeys.expr(root.lhs, memo)
+	if err != nil {
+		return nil, nil, err
+	}
+	rhs, err := monkeys.expr(root.rhs, memo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lhs, rhs, nil
+}
+
+// Make the expression for a monkey, with humn as the unknown, working out
+// any parts that do not depend on it. Expressions already made are kept in
+// memo, so that each monkey is only done once.
+func (monkeys Monkeys) expr(name string, memo map[string]*expr) (*expr, error) {
+	if e, ok := memo[name]; ok {
+		return e, nil
+	}
+	var e *expr
+	m := monkeys[name]
+	if name == "humn" {
+		e = &expr{op: "x"}
+	} else if isNumber(m) {
+		e = &expr{num: new(big.Rat).SetInt64(m.num)}
+	} else {
+		lhs, err := monkeys.expr(m.lhs, memo)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := monkeys.expr(m.rhs, memo)
+		if err != nil {
+			return nil, err
+		}
+		e, err = fold(m.op, lhs, rhs)
+		if err != nil {
+			return nil, fmt.Errorf("monkey %s: %w", name, err)
+		}
+	}
+	memo[name] = e
+	return e, nil
+}
+
+// Make an operation on two expressions, simplifying it: work it out if
+// both are numbers, and leave out adding or subtracting 0, or multiplying
+// or dividing by 1
+func fold(op string, lhs, rhs *expr) (*expr, error) {
+	if !lhs.hasUnknown() && !rhs.hasUnknown() {
+		v := new(big.Rat)
+		switch op {
+		case "+":
+			v.Add(lhs.num, rhs.num)
+		case "-":
+			v.Sub(lhs.num, rhs.num)
+		case "*":
+			v.Mul(lhs.num, rhs.num)
+		case "/":
+			if rhs.num.Sign() == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			v.Quo(lhs.num, rhs.num)
+		}
+		return &expr{num: v}, nil
+	}
+	switch {
+	case (op == "+" || op == "-") && rhs.isNum(0), (op == "*" || op == "/") && rhs.isNum(1):
+		return lhs, nil
+	case op == "+" && lhs.isNum(0), op == "*" && lhs.isNum(1):
+		return rhs, nil
+	}
+	return &expr{op: op, lhs: lhs, rhs: rhs}, nil
+}
+
+// Does the expression contain the unknown?
+func (e *expr) hasUnknown() bool {
+	return e.op != ""
+}
+
+// Is the expression a given number?
+func (e *expr) isNum(n int64) bool {
+	return !e.hasUnknown() && e.num.Cmp(big.NewRat(n, 1)) == 0
+}
+
+// Show the expression as a formula, with brackets around each operation
+func (e *expr) String() string {
+	switch e.op {
+	case "":
+		return e.num.RatString()
+	case "x":
+		return "humn"
+	}
+	return "(" + e.lhs.String() + " " + e.op + " " + e.rhs.String() + ")"
+}
+
+// Show the equation for root as one formula, e.g.,
+// ((4 + (2 * (humn - 3))) / 4) = 150
+func (monkeys Monkeys) formula() (string, error) {
+	lhs, rhs, err := monkeys.equation()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v = %v", lhs, rhs), nil
+}
+
+// Write the equation for root as a Graphviz graph (e.g., to make a picture
+// of it with "dot -Tsvg"), with an arrow from each operation to its operands
+func (monkeys Monkeys) dot(w io.Writer) error {
+	lhs, rhs, err := monkeys.equation()
+	if err != nil {
+		return err
+	}
+	ids := map[*expr]int{} // node number for each expression, each shown once
+	var node func(e *expr) int
+	node = func(e *expr) int {
+		if id, ok := ids[e]; ok {
+			return id
+		}
+		id := len(ids) + 1
+		ids[e] = id
+		switch e.op {
+		case "":
+			fmt.Fprintf(w, "\tn%d [label=%q shape=box];\n", id, e.num.RatString())
+		case "x":
+			fmt.Fprintf(w, "\tn%d [label=\"humn\" style=filled];\n", id)
+		default:
+			fmt.Fprintf(w, "\tn%d [label=%q];\n", id, e.op)
+			fmt.Fprintf(w, "\tn%d -> n%d;\n", id, node(e.lhs))
+			fmt.Fprintf(w, "\tn%d -> n%d;\n", id, node(e.rhs))
+		}
+		return id
+	}
+	fmt.Fprintln(w, "digraph root {")
+	fmt.Fprintln(w, "\troot [label=\"=\"];")
+	fmt.Fprintf(w, "\troot -> n%d;\n", node(lhs))
+	fmt.Fprintf(w, "\troot -> n%d;\n", node(rhs))
+	fmt.Fprintln(w, "}")
+	return nil
+}
@@ -0,0 +1,45 @@
+// Unit tests for the expressions of Day 21
+
+package day21
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCycles(t *testing.T) {
+	_, err := readMonkeys([]string{"root: aaaa + humn", "aaaa: bbbb * cccc", "bbbb: aaaa - humn", "cccc: 2", "humn: 1"})
+	if err == nil || err.Error() != "monkeys form a cycle: aaaa -> bbbb -> aaaa" {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestFormula(t *testing.T) {
+	for _, c := range []struct{ monkeys, want string }{
+		{"root: aaaa + bbbb; aaaa: humn * cccc; bbbb: 6; cccc: 3; humn: 5", "(humn * 3) = 6"},
+		{"root: aaaa + bbbb; aaaa: humn * cccc; bbbb: 6; cccc: 1; humn: 5", "humn = 6"},
+		{"root: aaaa + bbbb; aaaa: cccc + humn; bbbb: cccc / dddd; cccc: 1; dddd: 3; humn: 5", "(1 + humn) = 1/3"},
+		{"root: aaaa + bbbb; aaaa: cccc - cccc; bbbb: aaaa + humn; cccc: 7; humn: 5", "0 = humn"},
+	} {
+		monkeys, err := readMonkeys(strings.Split(c.monkeys, "; "))
+		if err != nil {
+			t.Fatalf("%s: %v", c.monkeys, err)
+		}
+		if f, err := monkeys.formula(); err != nil || f != c.want {
+			t.Errorf("%s: got %s, %v, want %s", c.monkeys, f, err, c.want)
+		}
+	}
+}
+
+// A monkey used twice is only shown once in the graph
+func TestDot(t *testing.T) {
+	monkeys, _ := readMonkeys([]string{"root: aaaa + bbbb", "aaaa: humn * bbbb", "bbbb: 6", "humn: 5"})
+	var b bytes.Buffer
+	if err := monkeys.dot(&b); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(b.String(), `label="6"`); n != 1 || !strings.HasPrefix(b.String(), "digraph") {
+		t.Errorf("graph has 6 %d times:\n%s", n, b.String())
+	}
+}
@@ -1,3 +0,0 @@
-module day21
-
-go 1.19
@@ -3,17 +3,31 @@
 // Given a list of variable names, each with either a numbers or
 // a simple formula, recursively evaluate the root node (Part 1),
 // and find the value for one cell that makes the two sides of the
-// root node equal (solved using gradient descent).
+// root node equal (first solved using gradient descent, now by turning
+// the monkeys into an expression and undoing it on the side with the
+// unknown, using exact fractions, then checking the answer with the kind
+// of numbers chosen).
 //
 // AK, 21 Dec 2022
 
-package main
+package day21
 
 import (
 	"fmt"
+	"io"
+	"math/big"
+	"sort"
 	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/num"
 )
 
+func init() {
+	aoc.Register(21, func() aoc.Solver { return &solver{} })
+}
+
 // A monkey has a name, and either a simple formula, or a number
 type Monkey struct {
 	name         string
@@ -22,57 +36,204 @@ type Monkey struct {
 }
 
 // A dictionary of monkeys, so can find by name
-var monkeys map[string]*Monkey
+type Monkeys map[string]*Monkey
 
-func main() {
+// The parsed input: dictionary of monkeys
+type solver struct {
+	monkeys Monkeys
+	num     string // kind of numbers to use (see package num)
+}
+
+// Options: num=int64 (checked, the default), big or rat
+func (s *solver) SetOption(name, value string) error {
+	if name != "num" {
+		return fmt.Errorf("unknown option %q", name)
+	}
+	s.num = value
+	return num.CheckKind(value)
+}
+
+// Read the input file into a dictionary of monkeys
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	s.monkeys, err = readMonkeys(lines)
+	return err
+}
+
+// Part 1: just get the value for "root", with the kind of numbers chosen
+// (s/b 152, 158731561459602)
+func (s *solver) Part1() (string, error) {
+	switch s.num {
+	case num.KindBig:
+		return part1[*big.Int](s.monkeys, num.BigInt{})
+	case num.KindRat:
+		return part1[*big.Rat](s.monkeys, num.BigRat{})
+	}
+	return part1[int64](s.monkeys, num.Int64{})
+}
 
-	// Read the input file into a dictionary of monkeys
-	fname := "sample.txt"
-	fname = "input.txt"
-	monkeys = map[string]*Monkey{}
-	for _, l := range readLines(fname) {
+func part1[T any](monkeys Monkeys, a num.Arith[T]) (string, error) {
+	v, err := process(monkeys, a, "root", map[string]T{})
+	if err != nil {
+		return "", err
+	}
+	return a.String(v), nil
+}
+
+// Ways of showing the input (see "aoc show"): the equation for root as one
+// formula, or as a Graphviz graph
+func (s *solver) Formats() []string {
+	return []string{"formula", "dot"}
+}
+
+func (s *solver) Show(w io.Writer, format string) error {
+	if format == "dot" {
+		return s.monkeys.dot(w)
+	}
+	f, err := s.monkeys.formula()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, f)
+	return err
+}
+
+// Read the lines into a dictionary of monkeys, checking that every monkey
+// referred to exists, and that no monkey depends on itself
+func readMonkeys(lines []string) (Monkeys, error) {
+	monkeys := Monkeys{}
+	for i, l := range lines {
 		words := strings.Split(l, " ")
+		if (len(words) != 2 && len(words) != 4) || !strings.HasSuffix(words[0], ":") {
+			return nil, aocutil.LineErrorf(i+1, "bad monkey: %q", l)
+		}
 		mname := words[0][:len(words[0])-1]
 		m := Monkey{name: mname}
 		if len(words) == 2 {
-			m.num = atoi64(words[1])
+			var err error
+			m.num, err = aocutil.ParseInt64(words[1])
+			if err != nil {
+				return nil, aocutil.AtLine(err, i+1, l)
+			}
 		} else {
 			m.lhs = words[1]
 			m.op = words[2]
 			m.rhs = words[3]
+			if !strings.Contains("+-*/", m.op) || len(m.op) != 1 {
+				return nil, aocutil.LineErrorf(i+1, "bad operator %q", m.op)
+			}
 		}
 		monkeys[m.name] = &m
 	}
+	for _, m := range monkeys {
+		for _, n := range []string{m.lhs, m.rhs} {
+			if len(n) > 0 && monkeys[n] == nil {
+				return nil, fmt.Errorf("monkey %s refers to unknown monkey %s", m.name, n)
+			}
+		}
+	}
+	for _, n := range []string{"root", "humn"} {
+		if monkeys[n] == nil {
+			return nil, fmt.Errorf("no monkey %s", n)
+		}
+	}
+	if err := monkeys.checkCycles(); err != nil {
+		return nil, err
+	}
+	return monkeys, nil
+}
 
-	// Part 1: just get the value for "root"
-	fmt.Println("Part 1:", process("root"))
-
-	// Part 2: find the value for monkey "humn" that would make the
-	// rhs and lhs for "root" equal (use gradient search).
-	// 3769668716710 too high, s/b 3769668716709 (subtract one as
-	// in sample output)
-	lhs := monkeys["root"].lhs
-	rhs := monkeys["root"].rhs
-	var delta int64 = 1000000000    // change by this much each step
-	var guess int64 = 3000000000000 // starting guess
-	iters := 0
-	for {
-		iters++
-		monkeys["humn"].num = guess
-		diff := process(lhs) - process(rhs)
-		//fmt.Println("guess =", guess, ", delta =", delta, ", diff =", diff)
-		if diff == 0 {
-			fmt.Println("Part 2: found", guess-1, "after", iters, "iterations")
-			break
-		} else if abs(diff) < delta*10 {
-			delta /= 10
-		} else if diff > 0 {
-			guess += delta
-		} else {
-			guess -= delta
+// Check that the monkeys form a DAG (directed acyclic graph), i.e., that
+// no monkey's formula leads back to itself, which would never finish
+func (monkeys Monkeys) checkCycles() error {
+	const (
+		unvisited = iota
+		visiting  // on the path being followed
+		done      // checked, no cycle from here
+	)
+	state := map[string]int{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					path = path[i:]
+					break
+				}
+			}
+			return fmt.Errorf("monkeys form a cycle: %s -> %s", strings.Join(path, " -> "), name)
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		m := monkeys[name]
+		if !isNumber(m) {
+			for _, n := range []string{m.lhs, m.rhs} {
+				if err := visit(n, append(path, name)); err != nil {
+					return err
+				}
+			}
 		}
+		state[name] = done
+		return nil
+	}
+
+	// Visit the monkeys in order of name, so that any error is the same
+	// every time
+	names := []string{}
+	for n := range monkeys {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := visit(n, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Part 2: find the value for monkey "humn" that would make the
+// rhs and lhs for "root" equal, by solving for it (see solve.go), then
+// check it with the kind of numbers chosen (s/b 301, 3769668716709)
+func (s *solver) Part2() (string, error) {
+	x, err := s.monkeys.solve()
+	if err != nil {
+		return "", err
+	}
+	switch s.num {
+	case num.KindBig:
+		return part2[*big.Int](s.monkeys, num.BigInt{}, new(big.Int).Set(x))
+	case num.KindRat:
+		return part2[*big.Rat](s.monkeys, num.BigRat{}, new(big.Rat).SetInt(x))
+	}
+	if !x.IsInt64() {
+		return "", fmt.Errorf("humn is %v: %w", x, num.ErrOverflow)
 	}
+	return part2[int64](s.monkeys, num.Int64{}, x.Int64())
+}
 
+// Put the value for humn back into the tree, and check that both sides of
+// root come out the same, with no overflow or inexact division on the way
+func part2[T any](monkeys Monkeys, a num.Arith[T], humn T) (string, error) {
+	memo := map[string]T{"humn": humn}
+	root := monkeys["root"]
+	lhs, err := process(monkeys, a, root.lhs, memo)
+	if err != nil {
+		return "", fmt.Errorf("humn is %s: %w", a.String(humn), err)
+	}
+	rhs, err := process(monkeys, a, root.rhs, memo)
+	if err != nil {
+		return "", fmt.Errorf("humn is %s: %w", a.String(humn), err)
+	}
+	if a.Cmp(lhs, rhs) != 0 {
+		return "", fmt.Errorf("humn is %s, but root compares %s with %s", a.String(humn), a.String(lhs), a.String(rhs))
+	}
+	return a.String(humn), nil
 }
 
 // Determine if this monkey is a number
@@ -80,23 +241,42 @@ func isNumber(m *Monkey) bool {
 	return len(m.op) == 0
 }
 
-// Process (recursively evaluate) a monkey, returning result
-func process(name string) int64 {
+// Process (recursively evaluate) a monkey, returning result, with the
+// results so far in memo so that each monkey is only evaluated once. An
+// overflow, or a division that is not exact, is an error (except with
+// fractions, which are always exact).
+func process[T any](monkeys Monkeys, a num.Arith[T], name string, memo map[string]T) (T, error) {
+	if v, ok := memo[name]; ok {
+		return v, nil
+	}
 	m := monkeys[name]
 	if isNumber(m) {
-		return m.num
-	}
-	lhs := process(m.lhs)
-	rhs := process(m.rhs)
-	if m.op == "+" {
-		return lhs + rhs
-	} else if m.op == "-" {
-		return lhs - rhs
-	} else if m.op == "*" {
-		return lhs * rhs
-	} else if m.op == "/" {
-		return lhs / rhs
-	} else {
-		panic("Bad operator")
+		return a.Int(m.num), nil
+	}
+	lhs, err := process(monkeys, a, m.lhs, memo)
+	if err != nil {
+		return lhs, err
+	}
+	rhs, err := process(monkeys, a, m.rhs, memo)
+	if err != nil {
+		return rhs, err
+	}
+	var v T
+	switch m.op {
+	case "+":
+		v, err = a.Add(lhs, rhs)
+	case "-":
+		v, err = a.Sub(lhs, rhs)
+	case "*":
+		v, err = a.Mul(lhs, rhs)
+	case "/":
+		v, err = a.Div(lhs, rhs)
+	default:
+		return v, fmt.Errorf("monkey %s: bad operator %q", name, m.op)
+	}
+	if err != nil {
+		return v, fmt.Errorf("monkey %s: %w", name, err)
 	}
+	memo[name] = v
+	return v, nil
 }
@@ -0,0 +1,100 @@
+// Symbolic solution for Part 2 of Day 21: turn the monkeys into an
+// expression in the unknown "humn" (see expr.go), then undo the operations
+// one at a time on the path from "root" down to "humn".
+//
+// AK, Dec 2022
+
+package day21
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"adventofcode2022/aocutil"
+)
+
+// Errors from solving for humn
+var (
+	ErrNoSolution = errors.New("no integer solution")
+	ErrNotUnique  = errors.New("no unique solution")
+)
+
+// Find the value of humn that makes the two sides of root equal, which
+// must be a whole number
+func (monkeys Monkeys) solve() (*big.Int, error) {
+	lhs, rhs, err := monkeys.equation()
+	if err != nil {
+		return nil, err
+	}
+
+	// One side must be just a number, the target for the other side
+	if lhs.hasUnknown() && rhs.hasUnknown() {
+		return nil, fmt.Errorf("humn is on both sides of root")
+	}
+	if !lhs.hasUnknown() {
+		lhs, rhs = rhs, lhs
+	}
+	if !lhs.hasUnknown() {
+		if lhs.num.Cmp(rhs.num) == 0 {
+			return nil, ErrNotUnique // humn makes no difference
+		}
+		return nil, ErrNoSolution
+	}
+	x, err := lhs.invert(rhs.num)
+	if err != nil {
+		return nil, err
+	}
+	if !x.IsInt() {
+		return nil, fmt.Errorf("%w (humn would be %s)", ErrNoSolution, x.RatString())
+	}
+	return x.Num(), nil
+}
+
+// Find the value of the unknown that makes the expression equal to the
+// target, undoing the operations from the top down, e.g., if the
+// expression is a + 3, the target for a is the target less 3
+func (e *expr) invert(target *big.Rat) (*big.Rat, error) {
+	t := new(big.Rat).Set(target)
+	for e.op != "x" {
+
+		// k is the number on one side, the other side has the unknown
+		op := e.op
+		left := e.lhs.hasUnknown() // is the unknown on the left?
+		if left && e.rhs.hasUnknown() {
+			return nil, fmt.Errorf("humn is on both sides of %s, can't solve", op)
+		}
+		var k *big.Rat
+		if left {
+			k, e = e.rhs.num, e.lhs
+		} else {
+			k, e = e.lhs.num, e.rhs
+		}
+
+		// Work out what the side with the unknown must come to
+		switch {
+		case op == "+": // x + k = t, or k + x = t
+			t.Sub(t, k)
+		case op == "-" && left: // x - k = t
+			t.Add(t, k)
+		case op == "-": // k - x = t
+			t.Sub(k, t)
+		case op == "*": // x * k = t, or k * x = t
+			if k.Sign() == 0 {
+				return nil, aocutil.IfElse(t.Sign() == 0, ErrNotUnique, ErrNoSolution)
+			}
+			t.Quo(t, k)
+		case op == "/" && left: // x / k = t
+			if k.Sign() == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			t.Mul(t, k)
+		case op == "/": // k / x = t
+			if t.Sign() == 0 {
+				return nil, aocutil.IfElse(k.Sign() == 0, ErrNotUnique, ErrNoSolution)
+			}
+			t.Quo(k, t)
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,74 @@
+// Unit tests for solving Part 2 of Day 21
+
+package day21
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	for _, c := range []struct {
+		monkeys string // lines separated by semicolons
+		want    string // answer, or error
+	}{
+		{"root: humn + aaaa; aaaa: 5; humn: 1", "5"},
+		{"root: aaaa + bbbb; aaaa: humn - cccc; bbbb: 7; cccc: 3; humn: 0", "10"},
+		{"root: aaaa + bbbb; aaaa: bbbb / humn; bbbb: 4; humn: 0", "1"},
+		{"root: aaaa + bbbb; aaaa: humn / cccc; bbbb: 4; cccc: 3; humn: 0", "12"},
+		{"root: aaaa + bbbb; aaaa: humn * cccc; bbbb: 4; cccc: 3; humn: 0", "no integer solution"},
+		{"root: aaaa + bbbb; aaaa: humn * cccc; bbbb: 0; cccc: 0; humn: 0", "no unique solution"},
+		{"root: aaaa + humn; aaaa: humn * humn; humn: 0", "humn is on both sides"},
+	} {
+		lines := strings.Split(c.monkeys, "; ")
+		monkeys, err := readMonkeys(lines)
+		if err != nil {
+			t.Fatalf("%s: %v", c.monkeys, err)
+		}
+		x, err := monkeys.solve()
+		if err != nil {
+			if !strings.Contains(err.Error(), c.want) {
+				t.Errorf("%s: got error %v, want %s", c.monkeys, err, c.want)
+			}
+		} else if x.String() != c.want {
+			t.Errorf("%s: got %s, want %s", c.monkeys, x, c.want)
+		}
+	}
+
+	// The kind of error can be checked
+	monkeys, _ := readMonkeys([]string{"root: aaaa + cccc", "aaaa: humn * bbbb", "bbbb: 2", "cccc: 3", "humn: 1"})
+	if _, err := monkeys.solve(); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("got %v instead of ErrNoSolution", err)
+	}
+}
+
+// Part 2 checks the answer with the kind of numbers chosen
+func TestPart2Kinds(t *testing.T) {
+	inexact := "root: aaaa + bbbb; aaaa: dddd * cccc; dddd: humn / eeee; eeee: 2; cccc: 2; bbbb: 3; humn: 0"
+	overflow := "root: aaaa + bbbb; aaaa: humn - cccc; bbbb: 9223372036854775807; cccc: 1; humn: 0"
+	for _, c := range []struct {
+		monkeys, kind, want string
+	}{
+		{inexact, "int64", "inexact division"},
+		{inexact, "big", "inexact division"},
+		{inexact, "rat", "3"},
+		{overflow, "int64", "integer overflow"},
+		{overflow, "big", "9223372036854775808"},
+	} {
+		s := &solver{}
+		if err := s.SetOption("num", c.kind); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Parse(strings.NewReader(strings.ReplaceAll(c.monkeys, "; ", "\n"))); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.Part2()
+		if err != nil {
+			got = err.Error()
+		}
+		if !strings.Contains(got, c.want) {
+			t.Errorf("%s with %s: got %s, want %s", c.monkeys, c.kind, got, c.want)
+		}
+	}
+}
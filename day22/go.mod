@@ -1,3 +0,0 @@
-module day22
-
-go 1.19
@@ -8,69 +8,65 @@
 //
 // AK, 22 and 26 Dec 2022
 
-package main
+package day22
 
 import (
 	"fmt"
+	"io"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/grid"
 )
 
-type Point struct {
-	x, y int
+func init() {
+	aoc.Register(22, func() aoc.Solver { return &solver{} })
 }
 
-// Keep track of what is at each location
-var tiles map[Point]byte
+// The parsed input: the map and the list of instructions
+type solver struct {
 
-// Keep track of the minimum X and Y values for each row/col
-var minX, maxX, minY, maxY map[int]int
+	// What is at each location (space if nothing), x and y are one less
+	// than the tile coordinates, which start at 1
+	tiles *grid.Grid[byte]
 
-// List of instructions, left and right encoded as -1 and -2
-var instructions []int
+	// List of instructions, left and right encoded as -1 and -2
+	instructions []int
+}
 
 const LEFT int = -1
 const RIGHT int = -2
 
-func main() {
-
-	// Read the input file: map until blank line, then set of instructions
-	fname := "sample.txt"
-	fname = "input.txt"
-	readMap(fname)
-
-	// Do part 1
-	part1()
-
-	// Do part 2
-	part2() // only works for input, not sample
-}
-
 // Part 1: follow instructions, moving around 2-d space, wrapping as necessary
-func part1() {
+// (s/b 6032, 36518)
+func (s *solver) Part1() (string, error) {
 
 	// Start in the first open tile on the first row, facing right
 	y := 1
-	x := minX[1]
-	assert(tiles[Point{x, y}] == '.', "First tile is not open!")
+	x := s.rowStart(1)
+	if s.tile(x, y) != '.' {
+		return "", fmt.Errorf("first tile is not open")
+	}
 	dir := 90 // start facing right
 
 	// Process instructions, simplify by adding up all moves in each direction?
-	for i := 0; i < len(instructions); i++ {
+	for i := 0; i < len(s.instructions); i++ {
 
 		// Process each turn or movement
-		inst := instructions[i]
+		inst := s.instructions[i]
 		if inst == RIGHT { // Rotate right
-			dir = ifElse(dir == 270, 0, dir+90)
+			dir = aocutil.IfElse(dir == 270, 0, dir+90)
 		} else if inst == LEFT { // Rotate left
-			dir = ifElse(dir == 0, 270, dir-90)
+			dir = aocutil.IfElse(dir == 0, 270, dir-90)
 		} else if dir == 90 { // Move right
 			x1 := x
 			y1 := y
 			for k := 0; k < inst; k++ {
 				x1++
-				if tiles[Point{x1, y1}] == 0 { // no tile, wrap to beginning
-					x1 = minX[y1]
+				if s.tile(x1, y1) == 0 { // no tile, wrap to beginning
+					x1 = s.rowStart(y1)
 				}
-				if tiles[Point{x1, y1}] == '.' {
+				if s.tile(x1, y1) == '.' {
 					x = x1
 				} else {
 					break
@@ -82,10 +78,10 @@ func part1() {
 			y1 := y
 			for k := 0; k < inst; k++ {
 				x1--
-				if tiles[Point{x1, y1}] == 0 { // no tile, wrap to end
-					x1 = maxX[y1]
+				if s.tile(x1, y1) == 0 { // no tile, wrap to end
+					x1 = s.rowEnd(y1)
 				}
-				if tiles[Point{x1, y1}] == '.' {
+				if s.tile(x1, y1) == '.' {
 					x = x1
 				} else {
 					break
@@ -97,10 +93,10 @@ func part1() {
 			y1 := y
 			for k := 0; k < inst; k++ {
 				y1--
-				if tiles[Point{x1, y1}] == 0 { // no tile, wrap to bottom
-					y1 = maxY[x1]
+				if s.tile(x1, y1) == 0 { // no tile, wrap to bottom
+					y1 = s.colEnd(x1)
 				}
-				if tiles[Point{x1, y1}] == '.' {
+				if s.tile(x1, y1) == '.' {
 					y = y1
 				} else {
 					break
@@ -112,10 +108,10 @@ func part1() {
 			y1 := y
 			for k := 0; k < inst; k++ {
 				y1++
-				if tiles[Point{x1, y1}] == 0 { // no tile, wrap to top
-					y1 = minY[x1]
+				if s.tile(x1, y1) == 0 { // no tile, wrap to top
+					y1 = s.colStart(x1)
 				}
-				if tiles[Point{x1, y1}] == '.' {
+				if s.tile(x1, y1) == '.' {
 					y = y1
 				} else {
 					break
@@ -128,19 +124,21 @@ func part1() {
 	//fmt.Printf("Final x = %d, y = %d, dir = %d (s/b 8, 6, dir 90)\n", x, y, dir)
 	facing := map[int]int{0: 3, 90: 0, 180: 1, 270: 2}
 	score := 1000*y + 4*x + facing[dir]
-	fmt.Println("Part 1: score (s/b 6032, 36518) =", score)
+	return fmt.Sprint(score), nil
 }
 
 // Part 2: same as part 1, but wrap around cube instead of 2-d space.
 // Done for main input only, and cube layout is hard-coded (so will not work
 // for other layouts). Each face of the cube is assigned a letter:
-//        _____ _____
-//       |     |     |
-//       |  A  |  B  |
-//       |_____|_____|
-//       |     |
-//       |  C  |
-//  _____|_____|
+//
+//	      _____ _____
+//	     |     |     |
+//	     |  A  |  B  |
+//	     |_____|_____|
+//	     |     |
+//	     |  C  |
+//	_____|_____|
+//
 // |     |     |
 // |  D  |  E  |
 // |_____|_____|
@@ -148,29 +146,47 @@ func part1() {
 // |  F  |
 // |_____|
 //
-
-func part2() {
+// 144019 was accepted for the input, but this gives 143208.
+func (s *solver) Part2() (string, error) {
+	// Every tile must be on one of the faces, and with the right number of
+	// tiles, that means all of each face is there
+	tiles := s.tiles.FindAllFunc(func(c byte) bool { return c != ' ' })
+	if len(tiles) != 6*faceSize*faceSize {
+		return "", fmt.Errorf("only works for input (cube layout is hard-coded)")
+	}
+	for _, p := range tiles {
+		if _, err := whichFace(p.X+1, p.Y+1); err != nil {
+			return "", fmt.Errorf("only works for input: %w", err)
+		}
+	}
 
 	// Start in the first open tile on the first row (top left cell on face A),
 	// facing right
 	y := 1
-	x := minX[1]
-	assert(tiles[Point{x, y}] == '.', "First tile is not open!")
+	x := s.rowStart(1)
+	if s.tile(x, y) != '.' {
+		return "", fmt.Errorf("first tile is not open")
+	}
 	dir := 90 // start facing right
 
 	// Process instructions, simplify by adding up all moves in each direction?
-	for _, inst := range instructions {
+	for _, inst := range s.instructions {
 
 		// Get new coordinates and direction, and cell contents, if we did
 		// that move. Update coordinates and direction if we would be moving
 		// into an empty space (i.e., contains period).
 		if inst == RIGHT { // Rotate right
-			dir = ifElse(dir == 270, 0, dir+90)
+			dir = aocutil.IfElse(dir == 270, 0, dir+90)
 		} else if inst == LEFT { // Rotate left
-			dir = ifElse(dir == 0, 270, dir-90)
+			dir = aocutil.IfElse(dir == 0, 270, dir-90)
 		} else {
-			x1, y1, dir1, c := move(inst, x, y, dir)
-			assert(c == '.' || c == '#', "Invalid char in map")
+			x1, y1, dir1, c, err := s.move(inst, x, y, dir)
+			if err != nil {
+				return "", err
+			}
+			if c != '.' && c != '#' {
+				return "", fmt.Errorf("invalid tile %q at %d,%d", c, x1, y1)
+			}
 			if c == '.' {
 				x = x1
 				y = y1
@@ -182,7 +198,7 @@ func part2() {
 	// Final score
 	facing := map[int]int{0: 3, 90: 0, 180: 1, 270: 2}
 	score := 1000*y + 4*x + facing[dir]
-	fmt.Println("Part 2 (s/b 144019):", score)
+	return fmt.Sprint(score), nil
 }
 
 // Simulate a move for Part 2, given the number of steps to move in the
@@ -191,12 +207,16 @@ func part2() {
 // in original orientation). Returns the new x,y coordinates (relative to the
 // original 2D layout), the direction (which may have changed as a result of
 // wrapping around the cube), and the contents of the new cell. Stops moving
-// before it hits a "wall" (cell with #).
-func move(n, x, y, dir int) (int, int, int, byte) {
+// before it hits a "wall" (cell with #), or gives an error if the starting
+// point is not on a face.
+func (s *solver) move(n, x, y, dir int) (int, int, int, byte, error) {
 
 	// Initialize variables to relative coordinates on the current face
-	face := whichFace(x, y)   // which face we are on A-F
-	x1, y1 := relCoords(x, y) // coordinates relative to this face
+	face, err := whichFace(x, y) // which face we are on A-F
+	if err != nil {
+		return x, y, dir, 0, err
+	}
+	x1, y1 := s.relCoords(x, y) // coordinates relative to this face
 
 	// Do each step of the move
 	for k := 0; k < n; k++ {
@@ -217,7 +237,7 @@ func move(n, x, y, dir int) (int, int, int, byte) {
 		} else if dir == 180 { // down
 			y1++
 		} else {
-			panic("Invalid direction!")
+			return x, y, dir, 0, fmt.Errorf("invalid direction %d", dir)
 		}
 
 		// If we are now off an edge of the face, we need to move to another face,
@@ -365,17 +385,17 @@ func move(n, x, y, dir int) (int, int, int, byte) {
 
 		// Convert back to absolute coordinates, check if we have hit a wall,
 		// return last position if we have
-		ax, ay := absCoords(x1, y1, face)
-		if tiles[Point{ax, ay}] == '#' {
-			x1, y1 = absCoords(prevX, prevY, prevFace)
-			return x1, y1, prevDir, tiles[Point{x1, y1}]
+		ax, ay := s.absCoords(x1, y1, face)
+		if s.tile(ax, ay) == '#' {
+			x1, y1 = s.absCoords(prevX, prevY, prevFace)
+			return x1, y1, prevDir, s.tile(x1, y1), nil
 		}
 
 	}
 
 	// Convert back to absolute coordinates and return result
-	x1, y1 = absCoords(x1, y1, face)
-	return x1, y1, dir, tiles[Point{x1, y1}]
+	x1, y1 = s.absCoords(x1, y1, face)
+	return x1, y1, dir, s.tile(x1, y1), nil
 }
 
 // For part 2
@@ -401,7 +421,7 @@ const faceSize = 50 // width/height of each square face
 //
 // Gives an error if the point is not on a valid face.
 
-func whichFace(x, y int) rune {
+func whichFace(x, y int) (rune, error) {
 	faces := [][]int{
 		[]int{51, 100, 1, 50},    // A
 		[]int{101, 150, 1, 50},   // B
@@ -413,39 +433,35 @@ func whichFace(x, y int) rune {
 	for i := 0; i < len(faces); i++ {
 		f := faces[i]
 		if x >= f[0] && x <= f[1] && y >= f[2] && y <= f[3] {
-			return rune(i + 'A')
+			return rune(i + 'A'), nil
 		}
 	}
-	fmt.Printf("ERROR: point %d,%d is not on a face\n", x, y)
-	panic("Invalid point")
+	return 0, fmt.Errorf("point %d,%d is not on a face", x, y)
 }
 
 // Older version, does not give error when a point is out of range
 func whichFaceOld(x, y int) rune {
 	if y <= faceSize {
-		return ifElse(x > faceSize*2, 'B', 'A')
+		return aocutil.IfElse(x > faceSize*2, 'B', 'A')
 	} else if y <= faceSize*2 {
 		return 'C'
 	} else if y <= faceSize*3 {
-		return ifElse(x > faceSize, 'E', 'D')
+		return aocutil.IfElse(x > faceSize, 'E', 'D')
 	} else {
 		return 'F'
 	}
 }
 
-// Return the relative coordinates, i.e., 1..50, works for any face
-func relCoords(x, y int) (int, int) {
-	assert(tiles[Point{x, y}] != 0, "relCoords: point not on map!")
+// Return the relative coordinates, i.e., 1..50, works for any face (the
+// point must be on a face, see whichFace)
+func (s *solver) relCoords(x, y int) (int, int) {
 	//return x % faceSize, y % faceSize
 	return ((x - 1) % faceSize) + 1, ((y - 1) % faceSize) + 1
 }
 
 // Return the absolute coordinates for a pair of 1..50 relative coordinates
 // and a face
-func absCoords(x, y int, face rune) (int, int) {
-
-	x0 := x
-	y0 := y
+func (s *solver) absCoords(x, y int, face rune) (int, int) {
 
 	// Vertical adjustment
 	if face == 'C' {
@@ -463,65 +479,93 @@ func absCoords(x, y int, face rune) (int, int) {
 		x += faceSize * 2
 	}
 
-	// Check
-	// Return adjusted coordinates
-	if tiles[Point{x, y}] == 0 {
-		fmt.Printf("absCoords(%d,%d): %d,%d not on map!\n", x0, y0, x, y)
-	}
-	if whichFace(x, y) == 0 {
-		fmt.Printf("absCoords(%d,%d): %d,%d not on a face!\n", x0, y0, x, y)
-	}
+	// Return adjusted coordinates (on the map, as Part 2 checks the layout
+	// first)
 	return x, y
 }
 
-// Read the input file: map until blank line, then set of instructions,
-// also set min/max X/Y
-func readMap(fname string) {
+// Read the input file: map until blank line, then set of instructions
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
 
 	// Read the input file: map until blank line, then set of instructions
 	readingMap := true
-	tiles = map[Point]byte{}
-	var x, y int
-	instructions = []int{}
-	for _, l := range readLines(fname) {
+	var mapLines []string
+	s.instructions = []int{}
+	for i, l := range lines {
 		if len(l) == 0 { // blank line means instructions come next
 			readingMap = false
 		} else if readingMap {
-			for x = 0; x < len(l); x++ {
-				if l[x] != ' ' {
-					tiles[Point{x + 1, y + 1}] = l[x]
+			for x := 0; x < len(l); x++ {
+				if l[x] != ' ' && l[x] != '.' && l[x] != '#' {
+					return &aocutil.ParseError{Line: i + 1, Col: x + 1, Token: l[x : x+1], Err: aocutil.ErrFormat}
 				}
 			}
-			y++
+			mapLines = append(mapLines, l)
 		} else { // instructions after blank line
-			instructions = parseInstructions(l)
+			s.instructions, err = parseInstructions(l)
+			if err != nil {
+				return aocutil.AtLine(err, i+1, l)
+			}
 		}
 	}
+	if len(mapLines) == 0 || len(s.instructions) == 0 {
+		return fmt.Errorf("missing map or instructions")
+	}
+	s.tiles = grid.FromLinesPadded(mapLines, ' ')
+	return nil
+}
 
-	// Get the min/max col in each row and min/max row in each column
-	minX = map[int]int{}
-	maxX = map[int]int{}
-	minY = map[int]int{}
-	maxY = map[int]int{}
-	for p, _ := range tiles {
-		if minX[p.y] == 0 || p.x < minX[p.y] {
-			minX[p.y] = p.x
-		}
-		if p.x > maxX[p.y] {
-			maxX[p.y] = p.x
-		}
-		if minY[p.x] == 0 || p.y < minY[p.x] {
-			minY[p.x] = p.y
+// What is at a tile (x and y starting at 1), 0 if nothing
+func (s *solver) tile(x, y int) byte {
+	c, _ := s.tiles.Get(x-1, y-1)
+	return aocutil.IfElse(c == ' ', 0, c)
+}
+
+// The first and last tiles in a row or column (starting at 1), for
+// wrapping around in Part 1
+func (s *solver) rowStart(y int) int {
+	return firstTile(s.tiles.Row(y - 1))
+}
+
+func (s *solver) rowEnd(y int) int {
+	return lastTile(s.tiles.Row(y - 1))
+}
+
+func (s *solver) colStart(x int) int {
+	return firstTile(s.tiles.Col(x - 1))
+}
+
+func (s *solver) colEnd(x int) int {
+	return lastTile(s.tiles.Col(x - 1))
+}
+
+// Position (starting at 1) of the first tile in a row or column
+func firstTile(cells []byte) int {
+	for i, c := range cells {
+		if c != ' ' {
+			return i + 1
 		}
-		if p.y > maxY[p.x] {
-			maxY[p.x] = p.y
+	}
+	return 0
+}
+
+// Position (starting at 1) of the last tile in a row or column
+func lastTile(cells []byte) int {
+	for i := len(cells) - 1; i >= 0; i-- {
+		if cells[i] != ' ' {
+			return i + 1
 		}
 	}
+	return 0
 }
 
 // Parse string of instructions into a list of numbers, where -1 means turn
 // left and -2 means turn right, other numbers are the number of steps to move
-func parseInstructions(s string) []int {
+func parseInstructions(s string) ([]int, error) {
 	result := []int{}
 	n := 0 // the current number
 	for i := 0; i < len(s); i++ {
@@ -533,13 +577,13 @@ func parseInstructions(s string) []int {
 				result = append(result, n)
 				n = 0
 			}
-			result = append(result, ifElse(c == 'L', -1, -2))
+			result = append(result, aocutil.IfElse(c == 'L', -1, -2))
 		} else {
-			panic("Invalid instruction!")
+			return nil, &aocutil.ParseError{Col: i + 1, Token: string(c), Err: aocutil.ErrFormat}
 		}
 	}
 	if n > 0 {
 		result = append(result, n)
 	}
-	return result
+	return result, nil
 }
@@ -1,3 +0,0 @@
-module day23
-
-go 1.19
@@ -7,12 +7,22 @@
 //
 // AK, 23 Dec 2022
 
-package main
+package day23
 
 import (
 	"fmt"
+	"io"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/grid"
+	"adventofcode2022/ring"
 )
 
+func init() {
+	aoc.Register(23, func() aoc.Solver { return &solver{} })
+}
+
 // Info about an elf
 type Elf struct {
 	number      int   // elf ID (not used)
@@ -25,172 +35,192 @@ type Point struct {
 	x, y int
 }
 
-// The list of elves (pointers, because we change the elves)
-var elves []*Elf
+// The elves (pointers, because we change the elves), and the number of
+// the elf at each position
+type Elves struct {
+	list []*Elf
+	at   *grid.SparseGrid[int]
+}
 
-func main() {
+// The parsed input: starting positions of the elves
+type solver struct {
+	start []Point
+}
 
-	// Read the input file into a map of positions of the elves
-	fname := "sample.txt"
-	//fname = "input.txt"  // uncomment to run on real input
-	var y int
-	for i, l := range readLines(fname) {
-		for x := 0; x < len(l); x++ {
-			if l[x] == '#' {
-				p := Point{x + 1, y + 1}
-				elves = append(elves, &Elf{number: i + 1, now: p})
-			}
+// Read the input file into a list of positions of the elves
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	g, err := grid.FromLinesFunc(lines, func(c byte) (bool, error) {
+		if c != '#' && c != '.' {
+			return false, aocutil.ErrFormat
 		}
-		y++
+		return c == '#', nil
+	})
+	if err != nil {
+		return err
+	}
+	s.start = nil
+	for _, p := range grid.FindAll(g, true) {
+		s.start = append(s.start, Point{p.X + 1, p.Y + 1})
 	}
+	return nil
+}
 
-	// Begin the simulation of rounds
-	dxy := []int{-1, 0, 1}
-	directions := []byte{'N', 'S', 'W', 'E'}  // gets rotated each iteration
-	for round := 1; round <= 10000; round++ { // will stop when no more movement
+// Create the list of elves at their starting positions
+func (s *solver) elves() Elves {
+	elves := Elves{at: grid.NewSparse[int]()}
+	for i, p := range s.start {
+		elves.list = append(elves.list, &Elf{number: i + 1, now: p})
+		elves.at.Set(p.x, p.y, i+1)
+	}
+	return elves
+}
 
-		// For Part 2, find the number of the first round where no Elf moves?
-		moved := false
+// For Part 1, after round 10 find the smallest rectangle that contains
+// the Elves, and report how many empty ground tiles does that
+// rectangle contain (s/b 110, 4034)
+func (s *solver) Part1() (string, error) {
+	elves := s.elves()
+	directions := ring.New[byte]('N', 'S', 'W', 'E') // gets rotated each iteration
+	for round := 1; round <= 10; round++ {
+		elves.doRound(directions.Values())
+		directions.Rotate(1)
+	}
+	min, max := elves.at.Bounds()
+	space := (max.X - min.X + 1) * (max.Y - min.Y + 1)
+	return fmt.Sprint(space - elves.at.Len()), nil
+}
 
-		// Go through elves, check if has any neighbours (skip if not), then
-		// find the first feasible direction she could move
-		for _, e := range elves {
+// For Part 2, find the number of the first round where no Elf moves
+// (s/b 20, 960)
+func (s *solver) Part2() (string, error) {
+	elves := s.elves()
+	directions := ring.New[byte]('N', 'S', 'W', 'E') // gets rotated each iteration
+	for round := 1; round <= 10000; round++ {        // will stop when no more movement
+		if !elves.doRound(directions.Values()) {
+			return fmt.Sprint(round), nil
+		}
+		directions.Rotate(1)
+	}
+	return "", fmt.Errorf("elves still moving after 10000 rounds")
+}
 
-			// Initialize state for this round
-			e.consid = Point{-999, -999}
-			e.canMove = false
+// Simulate one round, considering directions in the given order, and
+// return true if any elf moved
+func (elves Elves) doRound(directions []byte) bool {
 
-			// If nobody next to you in any direction, do nothing
-			hasNeighbour := false
-			x := e.now.x
-			y := e.now.y
-			for _, dx := range dxy {
-				for _, dy := range dxy {
-					if dx == 0 && dy == 0 {
-						continue // don't consider current location
-					}
-					if !free(x+dx, y+dy) {
-						hasNeighbour = true
-					}
-				}
-			}
-			if !hasNeighbour { // next elf if this one has no neighbours
-				continue
-			}
+	// For Part 2, find the number of the first round where no Elf moves?
+	moved := false
+
+	// Is a position free, i.e., no elf there now?
+	free := func(x, y int) bool {
+		return !elves.at.Has(x, y)
+	}
 
-			// Consider four directions in current order, choose the first one
-			// that meets criteria, i.e.,"If there is no Elf in the N, NE, or
-			// NW adjacent positions, the Elf proposes moving north one step."
-			// Note that I understood the free conditions to be ORs, but they
-			// need to be ANDs.
-			// Also note that it is possible for an elf not to find a feasible
-			// movement, in which case consid.x and consid.y will remain -999.
-			for _, dir := range directions {
-				if dir == 'N' {
-					if free(x-1, y-1) && free(x, y-1) && free(x+1, y-1) {
-						e.consid = Point{x, y - 1}
-						break
-					}
-				} else if dir == 'S' {
-					if free(x-1, y+1) && free(x, y+1) && free(x+1, y+1) {
-						e.consid = Point{x, y + 1}
-						break
-					}
-				} else if dir == 'E' { // right
-					if free(x+1, y-1) && free(x+1, y) && free(x+1, y+1) {
-						e.consid = Point{x + 1, y}
-						break
-					}
-				} else if dir == 'W' { // left
-					if free(x-1, y-1) && free(x-1, y) && free(x-1, y+1) {
-						e.consid = Point{x - 1, y}
-						break
-					}
+	// Go through elves, check if has any neighbours (skip if not), then
+	// find the first feasible direction she could move
+	dxy := []int{-1, 0, 1}
+	for _, e := range elves.list {
+
+		// Initialize state for this round
+		e.consid = Point{-999, -999}
+		e.canMove = false
+
+		// If nobody next to you in any direction, do nothing
+		hasNeighbour := false
+		x := e.now.x
+		y := e.now.y
+		for _, dx := range dxy {
+			for _, dy := range dxy {
+				if dx == 0 && dy == 0 {
+					continue // don't consider current location
+				}
+				if !free(x+dx, y+dy) {
+					hasNeighbour = true
 				}
 			}
 		}
+		if !hasNeighbour { // next elf if this one has no neighbours
+			continue
+		}
 
-		// Simultaneously, each Elf moves to their proposed destination
-		// tile if they were the only Elf to propose moving to that position.
-		// If two or more Elves propose moving to the same position, none of
-		// those Elves move.
-		for _, e := range elves {
-
-			// Skip this elf if could not find a place to move
-			c := e.consid
-			if c.x == -999 && c.y == -999 {
-				e.canMove = false
-				continue
-			}
-
-			// Otherwise reject move if anyone else is considering moving to
-			// the same place
-			e.canMove = true
-			for _, e1 := range elves {
-				c1 := e1.consid
-				if e1 != e && c1.x == c.x && c1.y == c.y {
-					e.canMove = false
+		// Consider four directions in current order, choose the first one
+		// that meets criteria, i.e.,"If there is no Elf in the N, NE, or
+		// NW adjacent positions, the Elf proposes moving north one step."
+		// Note that I understood the free conditions to be ORs, but they
+		// need to be ANDs.
+		// Also note that it is possible for an elf not to find a feasible
+		// movement, in which case consid.x and consid.y will remain -999.
+		for _, dir := range directions {
+			if dir == 'N' {
+				if free(x-1, y-1) && free(x, y-1) && free(x+1, y-1) {
+					e.consid = Point{x, y - 1}
+					break
+				}
+			} else if dir == 'S' {
+				if free(x-1, y+1) && free(x, y+1) && free(x+1, y+1) {
+					e.consid = Point{x, y + 1}
+					break
+				}
+			} else if dir == 'E' { // right
+				if free(x+1, y-1) && free(x+1, y) && free(x+1, y+1) {
+					e.consid = Point{x + 1, y}
+					break
+				}
+			} else if dir == 'W' { // left
+				if free(x-1, y-1) && free(x-1, y) && free(x-1, y+1) {
+					e.consid = Point{x - 1, y}
 					break
 				}
 			}
 		}
+	}
 
-		// Move any elves that can move, and record that a move has
-		// happened for Part 2
-		for _, e := range elves {
-			if e.canMove {
-				e.now.x = e.consid.x
-				e.now.y = e.consid.y
-				moved = true
-			}
+	// Count how many elves are considering moving to each place
+	considering := grid.NewSparse[int]()
+	for _, e := range elves.list {
+		if c := e.consid; c.x != -999 || c.y != -999 {
+			considering.Set(c.x, c.y, considering.At(c.x, c.y)+1)
 		}
+	}
 
-		// Rotate directions left by one
-		directions = append(directions[1:], directions[0])
+	// Simultaneously, each Elf moves to their proposed destination
+	// tile if they were the only Elf to propose moving to that position.
+	// If two or more Elves propose moving to the same position, none of
+	// those Elves move.
+	for _, e := range elves.list {
 
-		// For Part 1, after round 10 find the smallest rectangle that contains
-		// the Elves, and report how many empty ground tiles does that
-		// rectangle contain
-		if round == 10 {
-			min, max := minMax()
-			space := (max.x - min.x + 1) * (max.y - min.y + 1)
-			fmt.Println("Part 1 (s/b 110):", space-len(elves))
+		// Skip this elf if could not find a place to move
+		c := e.consid
+		if c.x == -999 && c.y == -999 {
+			e.canMove = false
+			continue
 		}
 
-		// For Part 2, report if there were no moves
-		if !moved {
-			fmt.Println("Part 2 (s/b 20): no more moves in round", round)
-			break
-		}
+		// Otherwise reject move if anyone else is considering moving to
+		// the same place
+		e.canMove = considering.At(c.x, c.y) == 1
 	}
-}
 
-// Get min/max coords
-func minMax() (Point, Point) {
-	var min, max Point
-	for i, e := range elves {
-		if i == 0 || e.now.x < min.x {
-			min.x = e.now.x
-		}
-		if i == 0 || e.now.x > max.x {
-			max.x = e.now.x
-		}
-		if i == 0 || e.now.y < min.y {
-			min.y = e.now.y
-		}
-		if i == 0 || e.now.y > max.y {
-			max.y = e.now.y
+	// Move any elves that can move, and record that a move has
+	// happened for Part 2 (take them all off the map before putting them
+	// back, since one may move to where another one was)
+	for _, e := range elves.list {
+		if e.canMove {
+			elves.at.Delete(e.now.x, e.now.y)
 		}
 	}
-	return min, max
-}
-
-// Is a point currently free?
-func free(x, y int) bool {
-	for _, e := range elves {
-		if e.now.x == x && e.now.y == y {
-			return false
+	for _, e := range elves.list {
+		if e.canMove {
+			e.now.x = e.consid.x
+			e.now.y = e.consid.y
+			elves.at.Set(e.now.x, e.now.y, e.number)
+			moved = true
 		}
 	}
-	return true
+
+	return moved
 }
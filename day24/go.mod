@@ -1,3 +0,0 @@
-module day24
-
-go 1.19
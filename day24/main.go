@@ -10,20 +10,28 @@
 //
 // AK, 24 Dec 2022
 
-package main
+package day24
 
 import (
 	"fmt"
+	"io"
+	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
+	"adventofcode2022/grid"
 )
 
+func init() {
+	aoc.Register(24, func() aoc.Solver { return &solver{} })
+}
+
 // A terrain is the map at one point in time, with all its blizzards
 type Terrain struct {
-	blizzards []Blizzard // can't use a map, because may be more than one
+	blizzards []Blizzard       // can't use a map, because may be more than one
+	occupied  *grid.Grid[bool] // whether there is a blizzard at each location
 }
 
-// We will pre-compute all the terrains for each day
-var terrains []Terrain
-
 // Info about a blizzard
 type Blizzard struct {
 	p         Point // the location
@@ -35,73 +43,129 @@ type Point struct {
 	x, y int
 }
 
-// Min/max coordinates (starting from 1)
-var minX, maxX, minY, maxY int
-
-// Locations of the doors
-var entry, exit Point
-
-// Best optimization result found
-var bestOverall int
-
 // For keeping track of points we have visited
 type PointTime struct {
 	p    Point
 	time int
 }
 
-// History of points we have visited
-var visited map[PointTime]int
+// The parsed input, with all the terrains pre-computed
+type solver struct {
+
+	// We will pre-compute all the terrains for each day
+	terrains []Terrain
+
+	// The map, with walls around the edges (the inside starts at 1)
+	valley *grid.Grid[byte]
 
-func main() {
+	// Locations of the doors
+	entry, exit Point
+}
+
+// Part 1: minimal number of minutes to get from entry to exit
+// 176 too low, 258 too low, 373 right (s/b 18 for sample 2)
+func (s *solver) Part1() (string, error) {
+	ans1, err := s.trip(s.entry, s.exit, s.entry, 0)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(ans1), nil
+}
+
+// Parts 2: add the minimal amounts of time to go back to the
+// entrance, then back to the exit.
+// Important: don't start back at terrain 0, but continue from
+// where left off.
+// For sample 2, should be 18 + 23 + 13 = 54 minutes (997 for input)
+func (s *solver) Part2() (string, error) {
+
+	// Optimize to exit
+	ans1, err := s.trip(s.entry, s.exit, s.entry, 0)
+	if err != nil {
+		return "", err
+	}
+
+	// Optimize back to entry
+	ans2, err := s.trip(s.exit, s.entry, s.exit, ans1-1)
+	if err != nil {
+		return "", err
+	}
+
+	// Optimize back to exit
+	ans3, err := s.trip(s.entry, s.exit, s.exit, ans2-1)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(ans3), nil
+}
+
+// One trip through the valley, starting at a time: the best time at the
+// destination, or an error if there is no way there within the
+// precomputed terrains
+func (s *solver) trip(here, dest, start Point, t int) (int, error) {
+	best := s.optimize(here, dest, []Point{start}, t, map[PointTime]int{})
+	if best == 0 {
+		return 0, fmt.Errorf("no path to goal %d,%d from minute %d", dest.x, dest.y, t)
+	}
+	return best, nil
+}
 
-	// Read the input file into a map of positions of the blizzard,
-	// which becomes the first terrain
-	fname := "sample.txt"
-	fname = "sample2.txt"
-	//fname = "input.txt" // uncomment to run on real input
-	lines := readLines(fname)
+// Read the input file into a map of positions of the blizzard,
+// which becomes the first terrain, and precompute the terrain at
+// each step of the simulation
+func (s *solver) Parse(r io.Reader) error {
+	lines, err := aoc.Lines(r)
+	if err != nil {
+		return err
+	}
+	valley, err := grid.FromLinesFunc(lines, func(c byte) (byte, error) {
+		if !strings.Contains("#.<>^v", string(c)) {
+			return 0, aocutil.ErrFormat
+		}
+		return c, nil
+	})
+	if err != nil {
+		return err
+	}
+	if valley.Width() < 3 || valley.Height() < 3 {
+		return fmt.Errorf("map too small")
+	}
+	s.terrains = nil
 	t := Terrain{}
-	for y := 1; y < len(lines)-1; y++ {
-		l := lines[y]
-		for x := 1; x < len(l)-1; x++ {
-			if l[x] != '.' {
-				b := Blizzard{Point{x, y}, l[x]}
+	for y := 1; y < valley.Height()-1; y++ {
+		for x := 1; x < valley.Width()-1; x++ {
+			if c := valley.At(x, y); c != '.' && c != '#' {
+				b := Blizzard{Point{x, y}, c}
 				t.blizzards = append(t.blizzards, b)
 			}
 		}
 	}
-	terrains = append(terrains, t)
+	s.terrains = append(s.terrains, s.withOccupied(t, valley))
 
-	// Set the min/max x and y, and locations of entry and exit
-	minX = 1
-	minY = 1
-	maxX = len(lines[0]) - 2
-	maxY = len(lines) - 2
-	entry = Point{1, 0}
-	exit = Point{maxX, maxY + 1}
+	// Set the map, and locations of entry and exit
+	s.valley = valley
+	maxX, maxY := valley.Width()-2, valley.Height()-2
+	s.entry = Point{1, 0}
+	s.exit = Point{maxX, maxY + 1}
 
 	// Precompute the terrain at each step of the simulation
-	nterrains := (maxX + maxY) * 4 // this will be the maximum number of time steps
-	nterrains *= 3                 // for Part 2
-	fmt.Printf("Precomputing %d terrains\n", nterrains)
+	nterrains := (maxX + maxY) * 4   // this will be the maximum number of time steps
+	nterrains *= 3                   // for Part 2
 	for i := 1; i < nterrains; i++ { // increase as necessary
 
 		// Make a new terrain and simulate movement of each blizzard
-		t0 := terrains[len(terrains)-1] // start with the last terrain
-		t1 := Terrain{}                 // initialize a new one
+		t0 := s.terrains[len(s.terrains)-1] // start with the last terrain
+		t1 := Terrain{}                     // initialize a new one
 		for _, b := range t0.blizzards {
 			p1 := Point{b.p.x, b.p.y}
 			if b.direction == '>' {
-				p1.x = ifElse(p1.x == maxX, 1, p1.x+1)
+				p1.x = aocutil.IfElse(p1.x == maxX, 1, p1.x+1)
 			} else if b.direction == '<' {
-				p1.x = ifElse(p1.x == 1, maxX, p1.x-1)
+				p1.x = aocutil.IfElse(p1.x == 1, maxX, p1.x-1)
 			} else if b.direction == 'v' {
-				p1.y = ifElse(p1.y == maxY, 1, p1.y+1)
+				p1.y = aocutil.IfElse(p1.y == maxY, 1, p1.y+1)
 			} else if b.direction == '^' {
-				p1.y = ifElse(p1.y == 1, maxY, p1.y-1)
-			} else {
-				panic("Invalid direction!")
+				p1.y = aocutil.IfElse(p1.y == 1, maxY, p1.y-1)
 			}
 			b := Blizzard{p1, b.direction}
 			t1.blizzards = append(t1.blizzards, b)
@@ -109,64 +173,46 @@ func main() {
 		}
 
 		// Add blizzard to the new terrain
-		terrains = append(terrains, t1)
+		s.terrains = append(s.terrains, s.withOccupied(t1, valley))
 	}
+	return nil
+}
 
-	// Part 1: minimal number of minutes to get from entry to exit
-	// 176 too low, 258 too low, 373 right
-	fmt.Println("Part 1: Optimizing")
-	ans1 := optimize(entry, exit, []Point{entry}, 0)
-	fmt.Println("Part 1 (s/b 18 for sample 2, 373 for input) =", ans1)
-
-	// Parts 2: add the minimal amounts of time to go back to the
-	// entrance, then back to the exit.
-	// Important: don't start back at terrain 0, but continue from
-	// where left off.
-	// For sample 2, should be 18 + 23 + 13 = 54 minutes
-
-	fmt.Println("Part 2: Optimizing back to entry")
-	bestOverall = -1              // need to reinitialize this
-	visited = map[PointTime]int{} // need to reinitialize this
-	ans2 := optimize(exit, entry, []Point{exit}, ans1-1)
-	fmt.Printf("  took %d minutes\n", ans2)
-
-	fmt.Println("Part 2: Optimizing back to exit")
-	bestOverall = -1              // need to reinitialize this
-	visited = map[PointTime]int{} // need to reinitialize this
-	ans3 := optimize(entry, exit, []Point{exit}, ans2-1)
-	fmt.Printf("  took %d minutes\n", ans3)
-	fmt.Println("Part 2 (s/b 54) =", ans3)
+// Fill in the grid of locations with blizzards for a terrain
+func (s *solver) withOccupied(t Terrain, valley *grid.Grid[byte]) Terrain {
+	t.occupied = grid.New[bool](valley.Width(), valley.Height())
+	for _, b := range t.blizzards {
+		t.occupied.Set(b.p.x, b.p.y, true)
+	}
+	return t
+}
 
+// Can we be at a location, i.e., is it inside the map and not a wall? The
+// entry and exit are only allowed if they are the destination.
+func (s *solver) open(p, dest Point) bool {
+	if p == dest {
+		return true
+	}
+	c, ok := s.valley.Get(p.x, p.y)
+	return ok && c != '#' && p != s.entry && p != s.exit
 }
 
 // For the optimization, do a depth-first recursive search, subject to movement
 // of the blizzards at each step, and return the best possible time to the
-// destination
-func optimize(here, dest Point, path []Point, t int) int {
-
-	// Initialize first iteration (t = 0), needs to be "manually"
-	// if you want to re-run optimization from last starting point,
-	// i.e., not from zero
-	if t == 0 {
-		bestOverall = -1
-		visited = map[PointTime]int{}
-	}
+// destination. The history of points visited needs to be empty for each
+// new optimization.
+func (s *solver) optimize(here, dest Point, path []Point, t int, visited map[PointTime]int) int {
 
 	// If you have successfully reached the destination, return
-	// the time it took, report of best result so far, and optionally
-	// show the path taken
+	// the time it took, and optionally show the path taken
 	if here == dest {
-		if bestOverall == -1 || t < bestOverall {
-			fmt.Println("New best time", t)
-			//fmt.Println("  path:",  path)  // uncomment to show path
-			bestOverall = t
-		}
+		//fmt.Println("  path:",  path)  // uncomment to show path
 		return t
 	}
 
 	// No more terrains left, dead end (or increase the number of
 	// precomputed terrains)
-	if t >= len(terrains)-1 {
+	if t >= len(s.terrains)-1 {
 		return 0
 	}
 
@@ -180,28 +226,16 @@ func optimize(here, dest Point, path []Point, t int) int {
 
 	// Find out where can we go from here, that will not be blocked in
 	// the next time step
-	t1 := terrains[t+1]            // look at next period's terrain
+	t1 := s.terrains[t+1]          // look at next period's terrain
 	candidates := []Point{}        // list of candidates
 	if empty(here.x, here.y, t1) { // consider staying put, unless blizzard
 		candidates = append(candidates, here)
 	}
-	if here.y >= minY && here.y <= maxY && here.x > minX && empty(here.x-1, here.y, t1) { // left
-		candidates = append(candidates, Point{here.x - 1, here.y})
-	}
-	if here.y >= minY && here.y <= maxY && here.x < maxX && empty(here.x+1, here.y, t1) { // right
-		candidates = append(candidates, Point{here.x + 1, here.y})
-	}
-	if here.y > minY && empty(here.x, here.y-1, t1) { // up
-		candidates = append(candidates, Point{here.x, here.y - 1})
-	}
-	if here.y < maxY && empty(here.x, here.y+1, t1) { // down, non exit
-		candidates = append(candidates, Point{here.x, here.y + 1})
-	}
-	if dest == exit && here.x == exit.x && here.y == maxY { // down, to exit
-		candidates = append(candidates, Point{here.x, here.y + 1})
-	}
-	if dest == entry && here.x == entry.x && here.y == minY { // up, to entry
-		candidates = append(candidates, Point{here.x, here.y - 1})
+	for _, d := range grid.Dirs4 { // up, right, down, left
+		c := Point{here.x + d.X, here.y + d.Y}
+		if s.open(c, dest) && empty(c.x, c.y, t1) {
+			candidates = append(candidates, c)
+		}
 	}
 
 	// Recursively try out each possible move from this place/time
@@ -210,8 +244,8 @@ func optimize(here, dest Point, path []Point, t int) int {
 		path1 := path
 		//path1 := copyPath(path) // uncomment these two lines to show each
 		//path1 = append(path1, here) // path found (for debugging)
-		o := optimize(c, dest, path1, t+1)    // optimize from here/now
-		if best == 0 || (o > 0 && o < best) { // is this destination better?
+		o := s.optimize(c, dest, path1, t+1, visited) // optimize from here/now
+		if best == 0 || (o > 0 && o < best) {         // is this destination better?
 			best = o
 		}
 	}
@@ -223,12 +257,8 @@ func optimize(here, dest Point, path []Point, t int) int {
 
 // Is a location empty?
 func empty(x, y int, t Terrain) bool {
-	for _, b := range t.blizzards {
-		if b.p.x == x && b.p.y == y {
-			return false
-		}
-	}
-	return true
+	occupied, _ := t.occupied.Get(x, y)
+	return !occupied
 }
 
 // Everything below his is just for debugging, can be removed
@@ -243,20 +273,21 @@ func copyPath(path []Point) []Point {
 }
 
 // Draw map (for debugging)
-func draw(t Terrain) {
-	for y := minY; y <= maxY; y++ {
-		for x := minX; x <= maxX; x++ {
+func (s *solver) draw(t Terrain) {
+	g := s.valley.Clone()
+	for y := 1; y < g.Height()-1; y++ {
+		for x := 1; x < g.Width()-1; x++ {
 			conts := contents(x, y, t)
 			if len(conts) == 0 {
-				fmt.Print(".")
+				g.Set(x, y, '.')
 			} else if len(conts) == 1 {
-				fmt.Print(string(conts[0]))
+				g.Set(x, y, conts[0])
 			} else {
-				fmt.Printf("%d", len(conts))
+				g.Set(x, y, byte('0'+len(conts)))
 			}
 		}
-		fmt.Println()
 	}
+	fmt.Print(g)
 }
 
 // Return the contents(s) of given location on a terrain
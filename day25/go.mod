@@ -1,3 +0,0 @@
-module day25
-
-go 1.19
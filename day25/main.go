@@ -7,35 +7,65 @@
 //
 // AK, 25 Dec 2022
 
-package main
+package day25
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+
+	"adventofcode2022/aoc"
+	"adventofcode2022/aocutil"
 )
 
+func init() {
+	aoc.Register(25, func() aoc.Solver { return &solver{} })
+}
+
 // Pairs, for testing
 type Pair struct {
 	SNAFU   string
 	decimal int
 }
 
-func main() {
-
-	// Uncomment this to run the tests, which were used to figure out
-	// the encoding/decoding
-	//doTests()
+// The parsed input: just the sum of the SNAFU numbers, added up as the
+// input is read, so it can be of any size
+type solver struct {
+	sum int
+}
 
-	// Part 1: read the input file, convert each row to decimal and add them
-	// up, convert sum back to SNAFU
-	// Input: 28115957264952  =>  122-12==0-01=00-0=02
-	fname := "sample.txt"
-	fname = "input.txt" // uncomment to run on real input
-	part1 := 0
-	for _, l := range readLines(fname) {
-		part1 += convert(l)
+// Read the input file, one SNAFU number per line
+func (s *solver) Parse(r io.Reader) error {
+	in := aocutil.NewReader(r)
+	s.sum = 0
+	for in.Next() {
+		l := in.Text()
+		if len(l) == 0 {
+			continue
+		}
+		for j, c := range l {
+			if !strings.ContainsRune("012-=", c) {
+				return &aocutil.ParseError{Line: in.Line(), Col: j + 1, Token: string(c), Err: errors.New("invalid SNAFU digit")}
+			}
+		}
+		s.sum += convert(l)
 	}
-	fmt.Println("Part 1 (s/b 4890 => 2=-1=0):", part1, "=>", SNAFU(part1))
+	return in.Err()
+}
+
+// Part 1: convert each row to decimal and add them up, convert sum back
+// to SNAFU (s/b 4890 => 2=-1=0)
+// Input: 28115957264952  =>  122-12==0-01=00-0=02
+// (call doTests() to run the tests, which were used to figure out
+// the encoding/decoding)
+func (s *solver) Part1() (string, error) {
+	return SNAFU(s.sum), nil
+}
 
+// There is no Part 2, it was granted for free after completing the other days
+func (s *solver) Part2() (string, error) {
+	return "", aoc.ErrNoSolution
 }
 
 // Run a series of tests based on the problem input, decoding then
@@ -0,0 +1,3 @@
+module adventofcode2022
+
+go 1.19
@@ -0,0 +1,285 @@
+// Package graph is a directed graph with labelled nodes (e.g., valve names
+// such as "AA", or positions on a map) and weighted edges, with shortest
+// path searches: breadth-first, Dijkstra, A* and Floyd-Warshall. Nodes are
+// numbered from 0 in the order they are added, and the searches work on the
+// node numbers.
+//
+//	g := graph.New[string]()
+//	aa, bb := g.AddNode("AA"), g.AddNode("BB")
+//	g.AddBoth(aa, bb, 1)
+//	paths := g.Dijkstra(aa)
+//	fmt.Println(paths.Dist[bb], paths.Path(bb))
+//
+// AK, Dec 2022
+
+package graph
+
+import (
+	"container/heap"
+)
+
+// Distance to a node that cannot be reached
+const Unreachable = -1
+
+// An edge to another node, with its cost (weight)
+type Edge struct {
+	To, Cost int
+}
+
+// A graph with nodes labelled by any comparable type
+type Graph[L comparable] struct {
+	labels []L       // label of each node
+	index  map[L]int // node number for each label
+	edges  [][]Edge  // edges from each node
+}
+
+// Create an empty graph
+func New[L comparable]() *Graph[L] {
+	return &Graph[L]{index: map[L]int{}}
+}
+
+// Add a node with a label, return its number (the existing one if there
+// is already a node with that label)
+func (g *Graph[L]) AddNode(label L) int {
+	if n, ok := g.index[label]; ok {
+		return n
+	}
+	n := len(g.labels)
+	g.labels = append(g.labels, label)
+	g.edges = append(g.edges, nil)
+	g.index[label] = n
+	return n
+}
+
+// Number of the node with a label, false if there is none
+func (g *Graph[L]) Node(label L) (int, bool) {
+	n, ok := g.index[label]
+	return n, ok
+}
+
+// Label of a node
+func (g *Graph[L]) Label(n int) L {
+	return g.labels[n]
+}
+
+// Number of nodes
+func (g *Graph[L]) Len() int {
+	return len(g.labels)
+}
+
+// Add an edge from one node to another, with a cost
+func (g *Graph[L]) AddEdge(from, to, cost int) {
+	g.edges[from] = append(g.edges[from], Edge{to, cost})
+}
+
+// Add edges in both directions between two nodes, with the same cost
+func (g *Graph[L]) AddBoth(a, b, cost int) {
+	g.AddEdge(a, b, cost)
+	g.AddEdge(b, a, cost)
+}
+
+// Edges from a node
+func (g *Graph[L]) Edges(n int) []Edge {
+	return g.edges[n]
+}
+
+// Shortest paths from one or more sources to every node
+type Paths struct {
+	Dist []int // distance to each node from the nearest source, or Unreachable
+	Prev []int // previous node on the shortest path to each node, -1 for none
+}
+
+func newPaths(n int) *Paths {
+	p := &Paths{make([]int, n), make([]int, n)}
+	for i := 0; i < n; i++ {
+		p.Dist[i] = Unreachable
+		p.Prev[i] = -1
+	}
+	return p
+}
+
+// Shortest path to a node, from the nearest source to the node itself,
+// nil if it cannot be reached
+func (p *Paths) Path(to int) []int {
+	if p.Dist[to] == Unreachable {
+		return nil
+	}
+	path := []int{}
+	for n := to; n >= 0; n = p.Prev[n] {
+		path = append(path, n)
+	}
+	reverse(path)
+	return path
+}
+
+// Breadth-first search from one or more sources, counting steps (ignores
+// the costs of the edges)
+func (g *Graph[L]) BFS(from ...int) *Paths {
+	p := newPaths(g.Len())
+	queue := []int{}
+	for _, n := range from {
+		if p.Dist[n] == Unreachable {
+			p.Dist[n] = 0
+			queue = append(queue, n)
+		}
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, e := range g.edges[n] {
+			if p.Dist[e.To] == Unreachable {
+				p.Dist[e.To] = p.Dist[n] + 1
+				p.Prev[e.To] = n
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	return p
+}
+
+// Dijkstra's shortest paths from one or more sources, using the costs of
+// the edges (which must not be negative)
+func (g *Graph[L]) Dijkstra(from ...int) *Paths {
+	p := newPaths(g.Len())
+	pq := &queue{}
+	for _, n := range from {
+		p.Dist[n] = 0
+		heap.Push(pq, item{n, 0})
+	}
+	g.search(p, pq, -1, nil)
+	return p
+}
+
+// A* search for the shortest path between two nodes, guided by an estimate
+// h of the distance from each node to the destination. The estimate must be
+// consistent: never more than the cost of an edge plus the estimate from
+// the other end (e.g., Manhattan distance on a map with steps of 1), as
+// nodes are not searched again once taken off the queue. Returns the path
+// and its length, or nil and Unreachable.
+func (g *Graph[L]) AStar(from, to int, h func(n int) int) ([]int, int) {
+	p := newPaths(g.Len())
+	p.Dist[from] = 0
+	pq := &queue{}
+	heap.Push(pq, item{from, h(from)})
+	g.search(p, pq, to, h)
+	return p.Path(to), p.Dist[to]
+}
+
+// Best-first search used by Dijkstra and A*: take the node with the lowest
+// priority (distance, plus estimate h for A*) off the queue and relax its
+// edges, stopping when the destination (if any) is reached
+func (g *Graph[L]) search(p *Paths, pq *queue, to int, h func(int) int) {
+	done := make([]bool, g.Len())
+	for pq.Len() > 0 {
+		n := heap.Pop(pq).(item).node
+		if done[n] {
+			continue // already found a shorter way here
+		}
+		done[n] = true
+		if n == to {
+			return
+		}
+		for _, e := range g.edges[n] {
+			d := p.Dist[n] + e.Cost
+			if p.Dist[e.To] == Unreachable || d < p.Dist[e.To] {
+				p.Dist[e.To] = d
+				p.Prev[e.To] = n
+				if h != nil {
+					d += h(e.To)
+				}
+				heap.Push(pq, item{e.To, d})
+			}
+		}
+	}
+}
+
+// Shortest paths between all pairs of nodes
+type AllPaths struct {
+	dist [][]int // distance from each node to each other node
+	next [][]int // next node on the shortest path, -1 if none
+}
+
+// Floyd-Warshall shortest paths between all pairs of nodes, using the
+// costs of the edges
+func (g *Graph[L]) FloydWarshall() *AllPaths {
+	n := g.Len()
+	ap := &AllPaths{make([][]int, n), make([][]int, n)}
+	for i := 0; i < n; i++ {
+		ap.dist[i] = make([]int, n)
+		ap.next[i] = make([]int, n)
+		for j := 0; j < n; j++ {
+			ap.dist[i][j] = Unreachable
+			ap.next[i][j] = -1
+		}
+		ap.dist[i][i] = 0
+		ap.next[i][i] = i
+		for _, e := range g.edges[i] {
+			if ap.dist[i][e.To] == Unreachable || e.Cost < ap.dist[i][e.To] {
+				ap.dist[i][e.To] = e.Cost
+				ap.next[i][e.To] = e.To
+			}
+		}
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			if ap.dist[i][k] == Unreachable {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if ap.dist[k][j] == Unreachable {
+					continue
+				}
+				d := ap.dist[i][k] + ap.dist[k][j]
+				if ap.dist[i][j] == Unreachable || d < ap.dist[i][j] {
+					ap.dist[i][j] = d
+					ap.next[i][j] = ap.next[i][k]
+				}
+			}
+		}
+	}
+	return ap
+}
+
+// Shortest distance from one node to another, or Unreachable
+func (ap *AllPaths) Dist(from, to int) int {
+	return ap.dist[from][to]
+}
+
+// Shortest path from one node to another, including both, nil if there
+// is none
+func (ap *AllPaths) Path(from, to int) []int {
+	if ap.next[from][to] < 0 {
+		return nil
+	}
+	path := []int{from}
+	for n := from; n != to; {
+		n = ap.next[n][to]
+		path = append(path, n)
+	}
+	return path
+}
+
+// Reverse a list of nodes in place
+func reverse(l []int) {
+	for i, j := 0, len(l)-1; i < j; i, j = i+1, j-1 {
+		l[i], l[j] = l[j], l[i]
+	}
+}
+
+// Priority queue of nodes for Dijkstra and A*, lowest priority first
+type item struct {
+	node, priority int
+}
+
+type queue []item
+
+func (q queue) Len() int           { return len(q) }
+func (q queue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q queue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x any)        { *q = append(*q, x.(item)) }
+func (q *queue) Pop() any {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
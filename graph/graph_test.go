@@ -0,0 +1,81 @@
+// Unit tests for the graph package
+
+package graph
+
+import (
+	"testing"
+
+	"adventofcode2022/aocutil"
+)
+
+// A small weighted graph:
+//
+//	A --1-- B --1-- C
+//	 \             /
+//	  ------5------     D (not connected)
+func testGraph() (*Graph[string], []int) {
+	g := New[string]()
+	nodes := []int{}
+	for _, l := range []string{"A", "B", "C", "D"} {
+		nodes = append(nodes, g.AddNode(l))
+	}
+	g.AddBoth(nodes[0], nodes[1], 1)
+	g.AddBoth(nodes[1], nodes[2], 1)
+	g.AddBoth(nodes[0], nodes[2], 5)
+	return g, nodes
+}
+
+func TestLabels(t *testing.T) {
+	g, _ := testGraph()
+	if n := g.AddNode("C"); n != 2 || g.Len() != 4 {
+		t.Errorf("AddNode of existing label gives %d, %d nodes", n, g.Len())
+	}
+	if n, ok := g.Node("B"); !ok || g.Label(n) != "B" {
+		t.Errorf("Node(B) gives %d, %v", n, ok)
+	}
+	if _, ok := g.Node("X"); ok {
+		t.Errorf("Node(X) should not be found")
+	}
+}
+
+func TestSearches(t *testing.T) {
+	g, n := testGraph()
+	a, b, c, d := n[0], n[1], n[2], n[3]
+
+	// BFS counts steps, so the direct edge to C is shortest
+	p := g.BFS(a)
+	if p.Dist[c] != 1 || !aocutil.Same(p.Path(c), []int{a, c}) || p.Dist[d] != Unreachable || p.Path(d) != nil {
+		t.Errorf("BFS gives distances %v, path to C %v", p.Dist, p.Path(c))
+	}
+
+	// Dijkstra and A* use the costs, so go through B
+	p = g.Dijkstra(a)
+	if p.Dist[c] != 2 || !aocutil.Same(p.Path(c), []int{a, b, c}) {
+		t.Errorf("Dijkstra gives distances %v, path to C %v", p.Dist, p.Path(c))
+	}
+	path, dist := g.AStar(a, c, func(n int) int { return 0 })
+	if dist != 2 || !aocutil.Same(path, []int{a, b, c}) {
+		t.Errorf("AStar gives %v, %d", path, dist)
+	}
+	if path, dist := g.AStar(a, d, func(n int) int { return 0 }); path != nil || dist != Unreachable {
+		t.Errorf("AStar to unreachable node gives %v, %d", path, dist)
+	}
+
+	// Multiple sources: nearest one to each node
+	p = g.Dijkstra(a, c)
+	if !aocutil.Same(p.Dist, []int{0, 1, 0, Unreachable}) {
+		t.Errorf("Dijkstra from A and C gives %v", p.Dist)
+	}
+	if p = g.BFS(b, d); !aocutil.Same(p.Dist, []int{1, 0, 1, 0}) || !aocutil.Same(p.Path(a), []int{b, a}) {
+		t.Errorf("BFS from B and D gives %v", p.Dist)
+	}
+
+	// All pairs
+	ap := g.FloydWarshall()
+	if ap.Dist(c, a) != 2 || !aocutil.Same(ap.Path(c, a), []int{c, b, a}) || ap.Dist(a, d) != Unreachable || ap.Path(d, a) != nil {
+		t.Errorf("FloydWarshall gives C to A %d via %v", ap.Dist(c, a), ap.Path(c, a))
+	}
+	if !aocutil.Same(ap.Path(b, b), []int{b}) {
+		t.Errorf("FloydWarshall gives path from B to B %v", ap.Path(b, b))
+	}
+}
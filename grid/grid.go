@@ -0,0 +1,298 @@
+// Package grid is a dense 2-d grid of any type of cell, for the days whose
+// input is a map: read from the input lines, look up cells by x (column)
+// and y (row) starting at 0, iterate over neighbours, and print it.
+//
+//	g, err := grid.FromLines(lines) // a Grid[byte]
+//	start, ok := grid.Find(g, 'S')
+//	for _, p := range g.Neighbours4(start.X, start.Y) {
+//		if g.At(p.X, p.Y) != '#' { ... }
+//	}
+//
+// AK, Dec 2022
+
+package grid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"adventofcode2022/aocutil"
+)
+
+// A position in a grid, X is the column and Y the row
+type Point struct {
+	X, Y int
+}
+
+// Add two points, e.g., a position and a direction
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Directions to the 4 neighbours of a cell: up, right, down, left
+var Dirs4 = []Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// Directions to the 8 neighbours of a cell, clockwise from up
+var Dirs8 = []Point{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+
+// A grid of cells, stored row by row
+type Grid[T any] struct {
+	w, h  int
+	cells []T
+}
+
+// Create a grid of the given width and height, with all cells zero
+func New[T any](w, h int) *Grid[T] {
+	return &Grid[T]{w, h, make([]T, w*h)}
+}
+
+// Create a grid of bytes from lines of the input, which must all be the
+// same length
+func FromLines(lines []string) (*Grid[byte], error) {
+	return FromLinesFunc(lines, func(c byte) (byte, error) { return c, nil })
+}
+
+// Create a grid of bytes from lines of the input that may be of different
+// lengths, padding the short lines at the end with pad (e.g., a space)
+func FromLinesPadded(lines []string, pad byte) *Grid[byte] {
+	w := 0
+	for _, l := range lines {
+		w = aocutil.Max([]int{w, len(l)})
+	}
+	g := New[byte](w, len(lines))
+	g.Fill(pad)
+	for y, l := range lines {
+		copy(g.Row(y), l)
+	}
+	return g
+}
+
+// Create a grid from lines of the input, which must all be the same
+// length, converting each character to a cell (e.g., digits to numbers).
+// The error is a *aocutil.ParseError with the position of any line of the
+// wrong length, or character that f does not accept.
+func FromLinesFunc[T any](lines []string, f func(c byte) (T, error)) (*Grid[T], error) {
+	if len(lines) == 0 {
+		return nil, errors.New("empty grid")
+	}
+	g := New[T](len(lines[0]), len(lines))
+	for y, l := range lines {
+		if len(l) != g.w {
+			return nil, aocutil.LineErrorf(y+1, "row is %d long instead of %d", len(l), g.w)
+		}
+		for x := 0; x < len(l); x++ {
+			v, err := f(l[x])
+			if err != nil {
+				return nil, &aocutil.ParseError{Line: y + 1, Col: x + 1, Token: l[x : x+1], Err: err}
+			}
+			g.cells[y*g.w+x] = v
+		}
+	}
+	return g, nil
+}
+
+// Width of the grid (number of columns)
+func (g *Grid[T]) Width() int {
+	return g.w
+}
+
+// Height of the grid (number of rows)
+func (g *Grid[T]) Height() int {
+	return g.h
+}
+
+// Is a position inside the grid?
+func (g *Grid[T]) In(x, y int) bool {
+	return x >= 0 && x < g.w && y >= 0 && y < g.h
+}
+
+// The cell at a position, which must be inside the grid
+func (g *Grid[T]) At(x, y int) T {
+	if !g.In(x, y) {
+		panic(fmt.Sprintf("grid: %d,%d is outside %dx%d grid", x, y, g.w, g.h))
+	}
+	return g.cells[y*g.w+x]
+}
+
+// The cell at a position, or the zero value and false if the position is
+// outside the grid
+func (g *Grid[T]) Get(x, y int) (T, bool) {
+	if !g.In(x, y) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[y*g.w+x], true
+}
+
+// Change the cell at a position, which must be inside the grid
+func (g *Grid[T]) Set(x, y int, v T) {
+	if !g.In(x, y) {
+		panic(fmt.Sprintf("grid: %d,%d is outside %dx%d grid", x, y, g.w, g.h))
+	}
+	g.cells[y*g.w+x] = v
+}
+
+// Set every cell to the same value
+func (g *Grid[T]) Fill(v T) {
+	for i := range g.cells {
+		g.cells[i] = v
+	}
+}
+
+// Make a copy of the grid, which can be changed without changing this one
+func (g *Grid[T]) Clone() *Grid[T] {
+	return &Grid[T]{g.w, g.h, append([]T{}, g.cells...)}
+}
+
+// The cells in a row, from left to right (not a copy, so changing them
+// changes the grid, but appending to it makes a copy rather than running
+// into the next row)
+func (g *Grid[T]) Row(y int) []T {
+	return g.cells[y*g.w : (y+1)*g.w : (y+1)*g.w]
+}
+
+// A copy of the cells in a column, from top to bottom
+func (g *Grid[T]) Col(x int) []T {
+	col := make([]T, g.h)
+	for y := 0; y < g.h; y++ {
+		col[y] = g.cells[y*g.w+x]
+	}
+	return col
+}
+
+// Positions of the up to 4 neighbours of a cell (up, right, down, left)
+// that are inside the grid
+func (g *Grid[T]) Neighbours4(x, y int) []Point {
+	return g.neighbours(x, y, Dirs4)
+}
+
+// Positions of the up to 8 neighbours of a cell, including diagonals,
+// that are inside the grid
+func (g *Grid[T]) Neighbours8(x, y int) []Point {
+	return g.neighbours(x, y, Dirs8)
+}
+
+func (g *Grid[T]) neighbours(x, y int, dirs []Point) []Point {
+	nbrs := make([]Point, 0, len(dirs))
+	for _, d := range dirs {
+		if g.In(x+d.X, y+d.Y) {
+			nbrs = append(nbrs, Point{x + d.X, y + d.Y})
+		}
+	}
+	return nbrs
+}
+
+// Position of the first cell (row by row) for which f is true
+func (g *Grid[T]) FindFunc(f func(T) bool) (Point, bool) {
+	for i, v := range g.cells {
+		if f(v) {
+			return Point{i % g.w, i / g.w}, true
+		}
+	}
+	return Point{}, false
+}
+
+// Positions of all the cells for which f is true, row by row
+func (g *Grid[T]) FindAllFunc(f func(T) bool) []Point {
+	var found []Point
+	for i, v := range g.cells {
+		if f(v) {
+			found = append(found, Point{i % g.w, i / g.w})
+		}
+	}
+	return found
+}
+
+// Position of the first cell (row by row) with a value, e.g., the 'S' in
+// a map
+func Find[T comparable](g *Grid[T], v T) (Point, bool) {
+	return g.FindFunc(func(c T) bool { return c == v })
+}
+
+// Positions of all the cells with a value, row by row
+func FindAll[T comparable](g *Grid[T], v T) []Point {
+	return g.FindAllFunc(func(c T) bool { return c == v })
+}
+
+// Make a new grid by converting each cell of a grid
+func Map[T, U any](g *Grid[T], f func(T) U) *Grid[U] {
+	g1 := New[U](g.w, g.h)
+	for i, v := range g.cells {
+		g1.cells[i] = f(v)
+	}
+	return g1
+}
+
+// A new grid with the rows and columns swapped
+func (g *Grid[T]) Transpose() *Grid[T] {
+	g1 := New[T](g.h, g.w)
+	for y := 0; y < g.h; y++ {
+		for x := 0; x < g.w; x++ {
+			g1.cells[x*g1.w+y] = g.cells[y*g.w+x]
+		}
+	}
+	return g1
+}
+
+// A new grid rotated 90 degrees clockwise
+func (g *Grid[T]) RotateRight() *Grid[T] {
+	g1 := New[T](g.h, g.w)
+	for y := 0; y < g.h; y++ {
+		for x := 0; x < g.w; x++ {
+			g1.cells[x*g1.w+(g.h-1-y)] = g.cells[y*g.w+x]
+		}
+	}
+	return g1
+}
+
+// A new grid rotated 90 degrees anti-clockwise
+func (g *Grid[T]) RotateLeft() *Grid[T] {
+	g1 := New[T](g.h, g.w)
+	for y := 0; y < g.h; y++ {
+		for x := 0; x < g.w; x++ {
+			g1.cells[(g.w-1-x)*g1.w+y] = g.cells[y*g.w+x]
+		}
+	}
+	return g1
+}
+
+// Show the grid one row per line, with each cell shown by f
+func (g *Grid[T]) Format(f func(T) string) string {
+	var sb strings.Builder
+	for y := 0; y < g.h; y++ {
+		for _, v := range g.Row(y) {
+			sb.WriteString(f(v))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Show the grid one row per line: bytes and runes as characters, true and
+// false as # and ., anything else as it prints, separated by spaces
+func (g *Grid[T]) String() string {
+	switch any(g.cells).(type) {
+	case []byte, []rune, []bool:
+		return g.Format(func(v T) string {
+			switch c := any(v).(type) {
+			case byte:
+				return string(c)
+			case rune:
+				return string(c)
+			}
+			return aocutil.IfElse(any(v).(bool), "#", ".")
+		})
+	}
+	var sb strings.Builder
+	for y := 0; y < g.h; y++ {
+		for x, v := range g.Row(y) {
+			if x > 0 {
+				sb.WriteString(" ")
+			}
+			fmt.Fprint(&sb, v)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,129 @@
+// Unit tests for the grid
+
+package grid
+
+import (
+	"errors"
+	"testing"
+
+	"adventofcode2022/aocutil"
+)
+
+func TestGrid(t *testing.T) {
+	g, err := FromLines([]string{"abc", "dSf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Width() != 3 || g.Height() != 2 || g.At(2, 1) != 'f' {
+		t.Errorf("FromLines gives %dx%d grid:\n%v", g.Width(), g.Height(), g)
+	}
+	if p, ok := Find(g, 'S'); !ok || p != (Point{1, 1}) {
+		t.Errorf("Find gives %v, %v", p, ok)
+	}
+	if _, ok := g.Get(3, 0); ok {
+		t.Errorf("Get outside the grid should fail")
+	}
+	if row, col := string(g.Row(1)), string(g.Col(2)); row != "dSf" || col != "cf" {
+		t.Errorf("Row gives %q, Col gives %q", row, col)
+	}
+	if _ = append(g.Row(0), 'x'); g.At(0, 1) != 'd' {
+		t.Errorf("appending to a row changes the next one")
+	}
+
+	// Neighbours at a corner and in the middle
+	if n := g.Neighbours4(0, 0); len(n) != 2 || n[0] != (Point{1, 0}) || n[1] != (Point{0, 1}) {
+		t.Errorf("Neighbours4 gives %v", n)
+	}
+	if n := g.Neighbours8(1, 0); len(n) != 5 {
+		t.Errorf("Neighbours8 gives %v", n)
+	}
+
+	// Transpose and rotate
+	tests := []struct {
+		name string
+		g    *Grid[byte]
+		want string
+	}{
+		{"Transpose", g.Transpose(), "ad\nbS\ncf\n"},
+		{"RotateRight", g.RotateRight(), "da\nSb\nfc\n"},
+		{"RotateLeft", g.RotateLeft(), "cf\nbS\nad\n"},
+		{"RotateRight x4", g.RotateRight().RotateRight().RotateRight().RotateRight(), "abc\ndSf\n"},
+	}
+	for _, test := range tests {
+		if s := test.g.String(); s != test.want {
+			t.Errorf("%s gives\n%s", test.name, s)
+		}
+	}
+
+	// Other kinds of cells
+	nums := Map(g, func(c byte) int { return int(c) - 'a' })
+	if s := nums.String(); s != "0 1 2\n3 -14 5\n" {
+		t.Errorf("Grid of numbers shows as\n%s", s)
+	}
+	if s := FromLinesPadded([]string{"ab", "c"}, ' ').String(); s != "ab\nc \n" {
+		t.Errorf("FromLinesPadded gives\n%q", s)
+	}
+}
+
+func TestGridErrors(t *testing.T) {
+	_, err := FromLines([]string{"abc", "de"})
+	if s := err.Error(); s != "2: row is 2 long instead of 3" {
+		t.Errorf("Error message is %s", s)
+	}
+	digit := func(c byte) (int, error) {
+		if c < '0' || c > '9' {
+			return 0, aocutil.ErrInt
+		}
+		return int(c - '0'), nil
+	}
+	_, err = FromLinesFunc([]string{"123", "4x6"}, digit)
+	if !errors.Is(err, aocutil.ErrInt) || err.Error() != `2:2: "x": invalid integer` {
+		t.Errorf("Error message is %v", err)
+	}
+}
+
+func TestSparseGrid(t *testing.T) {
+	g := NewSparse[byte]()
+	if lo, hi := g.Bounds(); lo != (Point{}) || hi != (Point{}) || g.Render(Style[byte]{}) != "" {
+		t.Errorf("Empty grid has bounds %v, %v", lo, hi)
+	}
+	g.Set(2, 1, '#')
+	g.Set(-1, 3, 'o')
+	g.Set(0, 0, 'o')
+	g.Set(0, 0, '#') // replaces o
+	if lo, hi := g.Bounds(); lo != (Point{-1, 0}) || hi != (Point{2, 3}) {
+		t.Errorf("Bounds are %v, %v", lo, hi)
+	}
+	if g.Len() != 3 || g.Count('#') != 2 || g.Count('o') != 1 || !g.Has(2, 1) || g.Has(1, 2) {
+		t.Errorf("Grid has %d cells, %d #, %d o", g.Len(), g.Count('#'), g.Count('o'))
+	}
+	isRock := func(c byte) bool { return c == '#' }
+	if n := g.CountIn(Point{-5, 0}, Point{5, 0}, isRock); n != 1 {
+		t.Errorf("CountIn row 0 gives %d", n)
+	}
+	if n := g.CountIn(Point{-1, 0}, Point{0, 3}, func(byte) bool { return true }); n != 2 {
+		t.Errorf("CountIn columns -1 to 0 gives %d", n)
+	}
+
+	// Render as is, with glyphs, and upside down
+	if s := g.Render(Style[byte]{Empty: '.'}); s != ".#..\n...#\n....\no...\n" {
+		t.Errorf("Render gives\n%s", s)
+	}
+	st := Style[byte]{Glyphs: map[byte]byte{'#': '@'}, YUp: true}
+	if s := g.RenderIn(Point{-1, 2}, Point{1, 3}, st); s != "#  \n   \n" {
+		t.Errorf("RenderIn gives\n%q", s)
+	}
+
+	// Bounding box shrinks after deleting a cell on its edge
+	g.Delete(-1, 3)
+	if lo, hi := g.Bounds(); lo != (Point{0, 0}) || hi != (Point{2, 1}) || g.Count('o') != 0 {
+		t.Errorf("Bounds after Delete are %v, %v", lo, hi)
+	}
+	g.Set(5, -2, '#')
+	if lo, hi := g.Bounds(); lo != (Point{0, -2}) || hi != (Point{5, 1}) {
+		t.Errorf("Bounds after Set are %v, %v", lo, hi)
+	}
+	if d := g.Dense(Point{0, 0}, Point{2, 1}); d.At(0, 0) != '#' || d.At(1, 0) != 0 || d.At(2, 1) != '#' {
+		t.Errorf("Dense gives\n%v", d)
+	}
+}
@@ -0,0 +1,206 @@
+// Sparse grids, for maps with no fixed size that are mostly empty, e.g.,
+// sand falling from a hole (Day 14), rocks falling into a chamber (Day 17),
+// or elves spreading out (Day 23). Only the cells that have been set are
+// stored, and the bounding box of those cells is kept as they are set.
+//
+//	space := grid.NewSparse[byte]()
+//	space.Set(500, 0, '+')
+//	min, max := space.Bounds()
+//	fmt.Print(space.Render(grid.Style[byte]{Empty: '.'}))
+//
+// AK, Dec 2022
+
+package grid
+
+import (
+	"strings"
+)
+
+// A sparse grid of cells, with x and y of any size (including negative)
+type SparseGrid[T comparable] struct {
+	cells    map[Point]T
+	counts   map[T]int // number of cells with each value
+	min, max Point     // bounding box of the cells
+	stale    bool      // bounding box needs to be found again after Delete
+}
+
+// Create an empty sparse grid
+func NewSparse[T comparable]() *SparseGrid[T] {
+	return &SparseGrid[T]{cells: map[Point]T{}, counts: map[T]int{}}
+}
+
+// Set the cell at a position, extending the bounding box if necessary
+func (g *SparseGrid[T]) Set(x, y int, v T) {
+	p := Point{x, y}
+	if old, ok := g.cells[p]; ok {
+		g.counts[old]--
+	} else if len(g.cells) == 0 {
+		g.min, g.max = p, p
+	} else if !g.stale {
+		g.extend(p)
+	}
+	g.cells[p] = v
+	g.counts[v]++
+}
+
+// The cell at a position, and whether it has been set
+func (g *SparseGrid[T]) Get(x, y int) (T, bool) {
+	v, ok := g.cells[Point{x, y}]
+	return v, ok
+}
+
+// The cell at a position, zero value if it has not been set
+func (g *SparseGrid[T]) At(x, y int) T {
+	return g.cells[Point{x, y}]
+}
+
+// Has the cell at a position been set?
+func (g *SparseGrid[T]) Has(x, y int) bool {
+	_, ok := g.cells[Point{x, y}]
+	return ok
+}
+
+// Remove the cell at a position, if it has been set
+func (g *SparseGrid[T]) Delete(x, y int) {
+	p := Point{x, y}
+	v, ok := g.cells[p]
+	if !ok {
+		return
+	}
+	delete(g.cells, p)
+	g.counts[v]--
+	if x == g.min.X || x == g.max.X || y == g.min.Y || y == g.max.Y {
+		g.stale = true // may have shrunk, find it again when needed
+	}
+}
+
+// Number of cells that have been set
+func (g *SparseGrid[T]) Len() int {
+	return len(g.cells)
+}
+
+// Number of cells with a given value
+func (g *SparseGrid[T]) Count(v T) int {
+	return g.counts[v]
+}
+
+// Smallest and largest x and y of the cells that have been set, i.e., the
+// corners of the bounding box (both zero if there are none)
+func (g *SparseGrid[T]) Bounds() (Point, Point) {
+	if g.stale {
+		g.stale = false
+		first := true
+		for p := range g.cells {
+			if first {
+				g.min, g.max = p, p
+				first = false
+			}
+			g.extend(p)
+		}
+	}
+	if len(g.cells) == 0 {
+		return Point{}, Point{}
+	}
+	return g.min, g.max
+}
+
+// Extend the bounding box to include a point
+func (g *SparseGrid[T]) extend(p Point) {
+	if p.X < g.min.X {
+		g.min.X = p.X
+	} else if p.X > g.max.X {
+		g.max.X = p.X
+	}
+	if p.Y < g.min.Y {
+		g.min.Y = p.Y
+	} else if p.Y > g.max.Y {
+		g.max.Y = p.Y
+	}
+}
+
+// Call f for each cell that has been set, in no particular order
+func (g *SparseGrid[T]) Each(f func(p Point, v T)) {
+	for p, v := range g.cells {
+		f(p, v)
+	}
+}
+
+// Number of cells that have been set in a rectangular region (corners
+// included) for which f is true, e.g., to count cells on one row
+func (g *SparseGrid[T]) CountIn(lo, hi Point, f func(T) bool) int {
+	n := 0
+	if (hi.X-lo.X+1)*(hi.Y-lo.Y+1) < len(g.cells) {
+		for y := lo.Y; y <= hi.Y; y++ { // region is smaller, look at each cell
+			for x := lo.X; x <= hi.X; x++ {
+				if v, ok := g.cells[Point{x, y}]; ok && f(v) {
+					n++
+				}
+			}
+		}
+	} else {
+		for p, v := range g.cells { // fewer cells, look at each one
+			if p.X >= lo.X && p.X <= hi.X && p.Y >= lo.Y && p.Y <= hi.Y && f(v) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// A dense copy of a rectangular region (corners included), with cells that
+// have not been set left as zero
+func (g *SparseGrid[T]) Dense(lo, hi Point) *Grid[T] {
+	d := New[T](hi.X-lo.X+1, hi.Y-lo.Y+1)
+	for p, v := range g.cells {
+		if d.In(p.X-lo.X, p.Y-lo.Y) {
+			d.Set(p.X-lo.X, p.Y-lo.Y, v)
+		}
+	}
+	return d
+}
+
+// How to show a sparse grid as text
+type Style[T comparable] struct {
+	Glyphs map[T]byte // character for each value (if nil, the value itself for bytes, otherwise #)
+	Empty  byte       // character for cells that have not been set (default space)
+	YUp    bool       // show the largest y at the top, e.g., for a chamber filled from the bottom
+}
+
+// Show the bounding box of the grid as text, one row per line
+func (g *SparseGrid[T]) Render(st Style[T]) string {
+	lo, hi := g.Bounds()
+	if g.Len() == 0 {
+		return ""
+	}
+	return g.RenderIn(lo, hi, st)
+}
+
+// Show a rectangular region of the grid (corners included) as text, one
+// row per line
+func (g *SparseGrid[T]) RenderIn(lo, hi Point, st Style[T]) string {
+	empty := st.Empty
+	if empty == 0 {
+		empty = ' '
+	}
+	var sb strings.Builder
+	for r := 0; r <= hi.Y-lo.Y; r++ {
+		y := lo.Y + r
+		if st.YUp {
+			y = hi.Y - r
+		}
+		for x := lo.X; x <= hi.X; x++ {
+			v, ok := g.cells[Point{x, y}]
+			if !ok {
+				sb.WriteByte(empty)
+			} else if c, ok := st.Glyphs[v]; ok {
+				sb.WriteByte(c)
+			} else if c, ok := any(v).(byte); ok && st.Glyphs == nil {
+				sb.WriteByte(c)
+			} else {
+				sb.WriteByte('#')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,220 @@
+// Package ilp is a small integer linear programming solver: the simplex
+// method for the linear relaxation, and branch and bound for the variables
+// that must be whole numbers. It is meant for small problems (a few hundred
+// variables and constraints), e.g., production plans for Day 19, with a
+// dense tableau and float64 arithmetic.
+//
+// A problem is to maximize Obj·x, subject to Rows[i]·x <= RHS[i] for each
+// constraint, and 0 <= x[j] <= Upper[j] for each variable, e.g.,
+//
+//	p := ilp.New(2)              // two variables, x and y
+//	p.Obj = []float64{5, 4}      // maximize 5x + 4y
+//	p.Add([]float64{6, 4}, 24)   // 6x + 4y <= 24
+//	p.Add([]float64{1, 2}, 6)    // x + 2y <= 6
+//	sol, err := p.Solve()        // x = 4, y = 0, value 20
+//
+// AK, Dec 2022
+
+package ilp
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// Kinds of error
+var (
+	ErrInfeasible = errors.New("no feasible solution")
+	ErrUnbounded  = errors.New("unbounded objective")
+	ErrTooLong    = errors.New("too many simplex iterations")
+)
+
+// How close a number must be to something to count as equal to it
+const eps = 1e-9
+
+// A problem: maximize Obj·x subject to Rows[i]·x <= RHS[i], and
+// 0 <= x[j] <= Upper[j], with x[j] a whole number if Int[j]
+type Problem struct {
+	Obj   []float64   // objective, to maximize
+	Rows  [][]float64 // constraints, each Rows[i]·x <= RHS[i]
+	RHS   []float64
+	Upper []float64 // upper bound of each variable, +Inf for none
+	Int   []bool    // which variables must be whole numbers
+}
+
+// A solution: the value of each variable, and of the objective
+type Solution struct {
+	X     []float64
+	Value float64
+	Nodes int // number of linear programs solved
+}
+
+// Make a problem with n variables, no constraints, and no upper bounds,
+// all of which must be whole numbers
+func New(n int) *Problem {
+	p := &Problem{Obj: make([]float64, n), Upper: make([]float64, n), Int: make([]bool, n)}
+	for j := range p.Upper {
+		p.Upper[j] = math.Inf(1)
+		p.Int[j] = true
+	}
+	return p
+}
+
+// Add a constraint, coefs·x <= rhs
+func (p *Problem) Add(coefs []float64, rhs float64) {
+	p.Rows = append(p.Rows, coefs)
+	p.RHS = append(p.RHS, rhs)
+}
+
+// Solve the linear relaxation, ignoring which variables must be whole
+// numbers
+func (p *Problem) SolveLP() (*Solution, error) {
+	lo := make([]float64, len(p.Obj))
+	x, v, err := p.relaxation(lo, p.Upper)
+	if err != nil {
+		return nil, err
+	}
+	return &Solution{X: x, Value: v, Nodes: 1}, nil
+}
+
+// Solve the problem, with branch and bound: solve the relaxation, and if
+// a variable that must be a whole number isn't, try the two problems with
+// it rounded down or up, giving up on any branch whose relaxation is no
+// better than the best whole solution found so far
+func (p *Problem) Solve() (*Solution, error) {
+	return p.SolveContext(context.Background())
+}
+
+// Solve the problem, giving up if the context is cancelled or its deadline
+// passes, in which case it returns the best whole solution found so far (nil
+// if none) with the context's error
+func (p *Problem) SolveContext(ctx context.Context) (*Solution, error) {
+
+	// If the objective can only be a whole number, a branch must beat the
+	// best by at least one
+	whole := true
+	for j, c := range p.Obj {
+		if c != 0 && (!p.Int[j] || c != math.Trunc(c)) {
+			whole = false
+		}
+	}
+
+	// Depth-first, with the bounds on the variables for each branch
+	type branch struct{ lo, hi []float64 }
+	stack := []branch{{make([]float64, len(p.Obj)), p.Upper}}
+	var best *Solution
+	nodes := 0
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			if best != nil {
+				best.Nodes = nodes
+			}
+			return best, err
+		}
+		b := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		nodes++
+		x, v, err := p.relaxation(b.lo, b.hi)
+		if err == ErrInfeasible {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if whole {
+			v = math.Floor(v + 1e-6)
+		}
+		if best != nil && v <= best.Value+eps {
+			continue
+		}
+
+		// Branch on the first variable that isn't a whole number (so a
+		// model can choose which to decide first by how it numbers them)
+		j := -1
+		for i, xi := range x {
+			if p.Int[i] && math.Abs(xi-math.Round(xi)) > 1e-6 {
+				j = i
+				break
+			}
+		}
+		if j < 0 {
+			for i := range x {
+				if p.Int[i] {
+					x[i] = math.Round(x[i])
+				}
+			}
+			best = &Solution{X: x, Value: v}
+			continue
+		}
+		down := branch{b.lo, append([]float64{}, b.hi...)}
+		down.hi[j] = math.Floor(x[j])
+		up := branch{append([]float64{}, b.lo...), b.hi}
+		up.lo[j] = math.Ceil(x[j])
+		stack = append(stack, down, up) // try rounding up first
+	}
+	if best == nil {
+		return nil, ErrInfeasible
+	}
+	best.Nodes = nodes
+	return best, nil
+}
+
+// Solve the linear relaxation with lo <= x <= hi, by leaving out the
+// variables fixed by their bounds, shifting the others so that their lower
+// bound is 0, and making each finite upper bound a constraint
+func (p *Problem) relaxation(lo, hi []float64) ([]float64, float64, error) {
+	if len(p.Rows) == 0 && len(p.Obj) == 0 {
+		return nil, 0, nil
+	}
+
+	// The variables left, and the objective for them
+	var free []int
+	var obj []float64
+	for j := range p.Obj {
+		if lo[j] > hi[j] {
+			return nil, 0, ErrInfeasible
+		}
+		if lo[j] < hi[j] {
+			free = append(free, j)
+			obj = append(obj, p.Obj[j])
+		}
+	}
+
+	// The constraints on them, less what the lower bounds use up
+	var rows [][]float64
+	var rhs []float64
+	for i, r := range p.Rows {
+		b := p.RHS[i]
+		row := make([]float64, len(free))
+		for j, a := range r {
+			b -= a * lo[j]
+		}
+		for k, j := range free {
+			row[k] = r[j]
+		}
+		rows = append(rows, row)
+		rhs = append(rhs, b)
+	}
+	for k, j := range free {
+		if !math.IsInf(hi[j], 1) {
+			row := make([]float64, len(free))
+			row[k] = 1
+			rows = append(rows, row)
+			rhs = append(rhs, hi[j]-lo[j])
+		}
+	}
+
+	// Solve, and put the values of all the variables back together
+	y, v, err := simplex(obj, rows, rhs)
+	if err != nil {
+		return nil, 0, err
+	}
+	x := append([]float64{}, lo...)
+	for k, j := range free {
+		x[j] += y[k]
+	}
+	for j, c := range p.Obj {
+		v += c * lo[j]
+	}
+	return x, v, nil
+}
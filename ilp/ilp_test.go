@@ -0,0 +1,87 @@
+// Unit tests for the ilp package
+
+package ilp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+
+	// The example in the package comment: x = 4, y = 0
+	p := New(2)
+	p.Obj = []float64{5, 4}
+	p.Add([]float64{6, 4}, 24)
+	p.Add([]float64{1, 2}, 6)
+	sol, err := p.Solve()
+	if err != nil || sol.Value != 20 || sol.X[0] != 4 || sol.X[1] != 0 {
+		t.Errorf("got %v, %v, want x = 4, y = 0, value 20", sol, err)
+	}
+
+	// The relaxation of the same problem: x = 3, y = 1.5
+	lp, err := p.SolveLP()
+	if err != nil || math.Abs(lp.Value-21) > 1e-9 {
+		t.Errorf("relaxation got %v, %v, want value 21", lp, err)
+	}
+
+	// Knapsack with 0/1 variables: the relaxation takes part of an item
+	p = New(4)
+	p.Obj = []float64{10, 13, 7, 8}
+	p.Add([]float64{5, 7, 4, 5}, 13)
+	for j := range p.Upper {
+		p.Upper[j] = 1
+	}
+	sol, err = p.Solve()
+	if err != nil || sol.Value != 23 || sol.Nodes < 2 {
+		t.Errorf("knapsack got %v, %v, want 23 after branching", sol, err)
+	}
+
+	// A constraint that needs phase 1: x + y >= 2, minimize x + 3y
+	p = New(2)
+	p.Obj = []float64{-1, -3}
+	p.Add([]float64{-1, -1}, -2)
+	p.Upper[0] = 1.5
+	sol, err = p.Solve()
+	if err != nil || sol.Value != -4 || sol.X[0] != 1 || sol.X[1] != 1 {
+		t.Errorf("got %v, %v, want x = 1, y = 1, value -4", sol, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	p := New(1)
+	p.Obj = []float64{1}
+	p.Add([]float64{-1}, -3) // x >= 3
+	p.Upper[0] = 2
+	if _, err := p.Solve(); !errors.Is(err, ErrInfeasible) {
+		t.Errorf("x >= 3 and x <= 2 gives %v", err)
+	}
+
+	// No whole number between 0.2 and 0.8
+	p = New(1)
+	p.Add([]float64{-5}, -1)
+	p.Add([]float64{5}, 4)
+	if _, err := p.Solve(); !errors.Is(err, ErrInfeasible) {
+		t.Errorf("0.2 <= x <= 0.8 gives %v", err)
+	}
+
+	p = New(2)
+	p.Obj = []float64{1, 1}
+	p.Add([]float64{1, -1}, 1)
+	if _, err := p.Solve(); !errors.Is(err, ErrUnbounded) {
+		t.Errorf("unbounded problem gives %v", err)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	p := New(2)
+	p.Obj = []float64{5, 4}
+	p.Add([]float64{6, 4}, 24)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if sol, err := p.SolveContext(ctx); sol != nil || !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled solve gives %v, %v", sol, err)
+	}
+}
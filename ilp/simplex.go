@@ -0,0 +1,182 @@
+// The simplex method, on a dense tableau, in two phases: first find a
+// feasible solution (if any constraint has a negative right hand side, the
+// origin isn't one), then improve it until it is optimal.
+//
+// AK, Dec 2022
+
+package ilp
+
+import "adventofcode2022/aocutil"
+
+// A simplex tableau: a row for each constraint, then the objective row,
+// and a column for each variable, slack and artificial variable, then the
+// right hand side. The objective row holds the reduced costs (negative for
+// a column that would improve the objective), and its right hand side the
+// value of the objective.
+type tableau struct {
+	t     [][]float64
+	basis []int // the variable in the basis for each row
+	m     int   // number of constraints
+	cols  int   // number of columns, without the right hand side
+}
+
+// Solve max c·x subject to A x <= b and x >= 0
+func simplex(c []float64, A [][]float64, b []float64) ([]float64, float64, error) {
+	m, n := len(A), len(c)
+	nart := 0
+	for _, bi := range b {
+		if bi < 0 {
+			nart++
+		}
+	}
+
+	// Add a slack variable for each constraint, which starts in the basis,
+	// except for a constraint with a negative right hand side, which is
+	// negated and starts with an artificial variable in the basis instead
+	tb := &tableau{m: m, cols: n + m + nart, basis: make([]int, m)}
+	art := n + m
+	for i := range A {
+		row := make([]float64, tb.cols+1)
+		sign := 1.0
+		if b[i] < 0 {
+			sign = -1
+		}
+		for j, a := range A[i] {
+			row[j] = sign * a
+		}
+		row[n+i] = sign
+		row[tb.cols] = sign * b[i]
+		if sign < 0 {
+			row[art] = 1
+			tb.basis[i] = art
+			art++
+		} else {
+			tb.basis[i] = n + i
+		}
+		tb.t = append(tb.t, row)
+	}
+	obj := make([]float64, tb.cols+1)
+	tb.t = append(tb.t, obj)
+
+	// Phase 1: maximize minus the sum of the artificial variables, which
+	// must get to 0, then move them out of the basis
+	if nart > 0 {
+		for i := 0; i < m; i++ {
+			if tb.basis[i] >= n+m {
+				for j := range obj {
+					obj[j] -= tb.t[i][j]
+				}
+				obj[tb.basis[i]] = 0
+			}
+		}
+		if err := tb.run(tb.cols); err != nil {
+			return nil, 0, err
+		}
+		if obj[tb.cols] < -1e-7 {
+			return nil, 0, ErrInfeasible
+		}
+		for i := 0; i < m; i++ {
+			if tb.basis[i] >= n+m {
+				for j := 0; j < n+m; j++ {
+					if aocutil.Abs(tb.t[i][j]) > eps {
+						tb.pivot(i, j)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// Phase 2: the real objective, in terms of the variables not in the
+	// basis, never letting an artificial variable back in
+	for j := range obj {
+		obj[j] = 0
+	}
+	for j := 0; j < n; j++ {
+		obj[j] = -c[j]
+	}
+	for i := 0; i < m; i++ {
+		if k := tb.basis[i]; k < n && c[k] != 0 {
+			for j := range obj {
+				obj[j] += c[k] * tb.t[i][j]
+			}
+		}
+	}
+	if err := tb.run(n + m); err != nil {
+		return nil, 0, err
+	}
+
+	// Read off the solution
+	x := make([]float64, n)
+	for i := 0; i < m; i++ {
+		if tb.basis[i] < n {
+			x[tb.basis[i]] = tb.t[i][tb.cols]
+		}
+	}
+	return x, obj[tb.cols], nil
+}
+
+// Pivot until the objective can't be improved, only bringing the first
+// "enter" columns into the basis. Uses the most negative reduced cost,
+// switching to Bland's rule (the first one) if it takes long, to avoid
+// going round in circles.
+func (tb *tableau) run(enter int) error {
+	obj := tb.t[tb.m]
+	limit := 50 * (tb.m + tb.cols)
+	for iter := 0; ; iter++ {
+		if iter > 2*limit {
+			return ErrTooLong
+		}
+		bland := iter > limit
+
+		// Column to bring into the basis
+		k := -1
+		for j := 0; j < enter; j++ {
+			if obj[j] < -eps && (k < 0 || !bland && obj[j] < obj[k]) {
+				k = j
+				if bland {
+					break
+				}
+			}
+		}
+		if k < 0 {
+			return nil // optimal
+		}
+
+		// Row whose variable leaves the basis: the first to get to 0 as
+		// the new one increases (lowest variable number on a tie)
+		r, ratio := -1, 0.0
+		for i := 0; i < tb.m; i++ {
+			if a := tb.t[i][k]; a > eps {
+				q := tb.t[i][tb.cols] / a
+				if r < 0 || q < ratio-eps || (q < ratio+eps && tb.basis[i] < tb.basis[r]) {
+					r, ratio = i, q
+				}
+			}
+		}
+		if r < 0 {
+			return ErrUnbounded
+		}
+		tb.pivot(r, k)
+	}
+}
+
+// Bring column k into the basis in row r
+func (tb *tableau) pivot(r, k int) {
+	pr := tb.t[r]
+	p := pr[k]
+	for j := range pr {
+		pr[j] /= p
+	}
+	for i, row := range tb.t {
+		if i == r || row[k] == 0 {
+			continue
+		}
+		f := row[k]
+		for j := range row {
+			row[j] -= f * pr[j]
+		}
+		row[k] = 0
+	}
+	tb.basis[r] = k
+}
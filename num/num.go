@@ -0,0 +1,215 @@
+// Package num gives a choice of numbers for the days that do arithmetic on
+// values from the input, so that an answer can be trusted (or rejected)
+// rather than quietly wrapping around: int64 checked for overflow and
+// inexact division, arbitrary size integers (math/big.Int), and exact
+// fractions (math/big.Rat). Code is written once against Arith[T], and run
+// with whichever kind of number is chosen, e.g.,
+//
+//	func total[T any](a num.Arith[T], vals []int64) (T, error) {
+//		sum := a.Int(0)
+//		for _, v := range vals {
+//			var err error
+//			if sum, err = a.Add(sum, a.Int(v)); err != nil {
+//				return sum, err
+//			}
+//		}
+//		return sum, nil
+//	}
+//
+// AK, Dec 2022
+
+package num
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Kinds of error, which can be checked with errors.Is
+var (
+	ErrOverflow = errors.New("integer overflow")
+	ErrInexact  = errors.New("inexact division")
+	ErrDivZero  = errors.New("division by zero")
+)
+
+// Names of the kinds of number, e.g., for an option that chooses one
+const (
+	KindInt64 = "int64" // checked int64, the default
+	KindBig   = "big"   // math/big.Int
+	KindRat   = "rat"   // math/big.Rat
+)
+
+// All the kinds of number
+var Kinds = []string{KindInt64, KindBig, KindRat}
+
+// Check that a kind of number is one of Kinds ("" means the default)
+func CheckKind(kind string) error {
+	switch kind {
+	case "", KindInt64, KindBig, KindRat:
+		return nil
+	}
+	return fmt.Errorf("unknown kind of number %q (can be %s, %s or %s)", kind, KindInt64, KindBig, KindRat)
+}
+
+// Arithmetic on one kind of number. The operations return a new value,
+// and never change their arguments.
+type Arith[T any] interface {
+	Int(n int64) T         // a whole number
+	Add(a, b T) (T, error) // a + b
+	Sub(a, b T) (T, error) // a - b
+	Mul(a, b T) (T, error) // a * b
+	Div(a, b T) (T, error) // a / b, which must be exact
+	Quo(a, b T) (T, error) // a / b rounded toward zero, like / on integers
+	Rem(a, b T) (T, error) // remainder of Quo, like % on integers
+	Cmp(a, b T) int        // -1, 0 or 1 as a < b, a == b or a > b
+	String(a T) string     // e.g., 42, or 1/3 for a fraction
+}
+
+// Checked int64: any result that does not fit, or division that is not
+// exact, is an error
+type Int64 struct{}
+
+func (Int64) Int(n int64) int64 { return n }
+
+func (Int64) Add(a, b int64) (int64, error) {
+	r := a + b
+	if (a > 0 && b > 0 && r < 0) || (a < 0 && b < 0 && r >= 0) {
+		return 0, fmt.Errorf("%d + %d: %w", a, b, ErrOverflow)
+	}
+	return r, nil
+}
+
+func (Int64) Sub(a, b int64) (int64, error) {
+	r := a - b
+	if (b > 0 && r > a) || (b < 0 && r < a) {
+		return 0, fmt.Errorf("%d - %d: %w", a, b, ErrOverflow)
+	}
+	return r, nil
+}
+
+func (Int64) Mul(a, b int64) (int64, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	r := a * b
+	if r/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, fmt.Errorf("%d * %d: %w", a, b, ErrOverflow)
+	}
+	return r, nil
+}
+
+func (i Int64) Div(a, b int64) (int64, error) {
+	r, err := i.Quo(a, b)
+	if err == nil && a%b != 0 {
+		return 0, fmt.Errorf("%d / %d: %w", a, b, ErrInexact)
+	}
+	return r, err
+}
+
+func (Int64) Quo(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("%d / 0: %w", a, ErrDivZero)
+	}
+	if a == math.MinInt64 && b == -1 {
+		return 0, fmt.Errorf("%d / %d: %w", a, b, ErrOverflow)
+	}
+	return a / b, nil
+}
+
+func (Int64) Rem(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("%d %% 0: %w", a, ErrDivZero)
+	}
+	if b == -1 {
+		return 0, nil // also avoids overflow for math.MinInt64
+	}
+	return a % b, nil
+}
+
+func (Int64) Cmp(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func (Int64) String(a int64) string { return strconv.FormatInt(a, 10) }
+
+// Integers of any size: only division that is not exact (or by zero) is
+// an error
+type BigInt struct{}
+
+func (BigInt) Int(n int64) *big.Int { return big.NewInt(n) }
+
+func (BigInt) Add(a, b *big.Int) (*big.Int, error) { return new(big.Int).Add(a, b), nil }
+func (BigInt) Sub(a, b *big.Int) (*big.Int, error) { return new(big.Int).Sub(a, b), nil }
+func (BigInt) Mul(a, b *big.Int) (*big.Int, error) { return new(big.Int).Mul(a, b), nil }
+
+func (BigInt) Div(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() == 0 {
+		return nil, fmt.Errorf("%v / 0: %w", a, ErrDivZero)
+	}
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() != 0 {
+		return nil, fmt.Errorf("%v / %v: %w", a, b, ErrInexact)
+	}
+	return q, nil
+}
+
+func (BigInt) Quo(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() == 0 {
+		return nil, fmt.Errorf("%v / 0: %w", a, ErrDivZero)
+	}
+	return new(big.Int).Quo(a, b), nil
+}
+
+func (BigInt) Rem(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() == 0 {
+		return nil, fmt.Errorf("%v %% 0: %w", a, ErrDivZero)
+	}
+	return new(big.Int).Rem(a, b), nil
+}
+
+func (BigInt) Cmp(a, b *big.Int) int    { return a.Cmp(b) }
+func (BigInt) String(a *big.Int) string { return a.String() }
+
+// Exact fractions: only division by zero is an error. Quo and Rem round
+// the quotient toward zero, as for integers.
+type BigRat struct{}
+
+func (BigRat) Int(n int64) *big.Rat { return big.NewRat(n, 1) }
+
+func (BigRat) Add(a, b *big.Rat) (*big.Rat, error) { return new(big.Rat).Add(a, b), nil }
+func (BigRat) Sub(a, b *big.Rat) (*big.Rat, error) { return new(big.Rat).Sub(a, b), nil }
+func (BigRat) Mul(a, b *big.Rat) (*big.Rat, error) { return new(big.Rat).Mul(a, b), nil }
+
+func (BigRat) Div(a, b *big.Rat) (*big.Rat, error) {
+	if b.Sign() == 0 {
+		return nil, fmt.Errorf("%v / 0: %w", a.RatString(), ErrDivZero)
+	}
+	return new(big.Rat).Quo(a, b), nil
+}
+
+func (r BigRat) Quo(a, b *big.Rat) (*big.Rat, error) {
+	q, err := r.Div(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).SetInt(new(big.Int).Quo(q.Num(), q.Denom())), nil
+}
+
+func (r BigRat) Rem(a, b *big.Rat) (*big.Rat, error) {
+	q, err := r.Quo(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).Sub(a, q.Mul(q, b)), nil
+}
+
+func (BigRat) Cmp(a, b *big.Rat) int    { return a.Cmp(b) }
+func (BigRat) String(a *big.Rat) string { return a.RatString() }
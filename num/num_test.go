@@ -0,0 +1,92 @@
+// Unit tests for the num package
+
+package num
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// Work out a*b + c / d - e % f with any kind of number
+func calc[T any](a Arith[T], v [6]int64) (string, error) {
+	p, err := a.Mul(a.Int(v[0]), a.Int(v[1]))
+	if err != nil {
+		return "", err
+	}
+	q, err := a.Div(a.Int(v[2]), a.Int(v[3]))
+	if err != nil {
+		return "", err
+	}
+	r, err := a.Rem(a.Int(v[4]), a.Int(v[5]))
+	if err != nil {
+		return "", err
+	}
+	s, err := a.Add(p, q)
+	if err != nil {
+		return "", err
+	}
+	s, err = a.Sub(s, r)
+	if err != nil {
+		return "", err
+	}
+	return a.String(s), nil
+}
+
+func TestKinds(t *testing.T) {
+	half := int64(math.MaxInt64 / 2)
+	for _, c := range []struct {
+		v            [6]int64
+		i64, bi, rat string // answer, or error
+	}{
+		{[6]int64{6, 7, 12, 4, 17, 5}, "43", "43", "43"},
+		{[6]int64{-6, 7, 12, -4, -17, 5}, "-43", "-43", "-43"},
+		{[6]int64{1, 2, 1, 3, 0, 5}, "inexact", "inexact", "7/3"},
+		{[6]int64{half, 3, 0, 1, 0, 1}, "overflow", "13835058055282163709", "13835058055282163709"},
+		{[6]int64{half, 2, half, 1, 0, 1}, "overflow", "13835058055282163709", "13835058055282163709"},
+		{[6]int64{1, 1, 1, 0, 0, 1}, "division by zero", "division by zero", "division by zero"},
+	} {
+		for i, a := range []func([6]int64) (string, error){
+			func(v [6]int64) (string, error) { return calc[int64](Int64{}, v) },
+			func(v [6]int64) (string, error) { return calc[*big.Int](BigInt{}, v) },
+			func(v [6]int64) (string, error) { return calc[*big.Rat](BigRat{}, v) },
+		} {
+			want := []string{c.i64, c.bi, c.rat}[i]
+			got, err := a(c.v)
+			if err != nil {
+				got = err.Error()
+			}
+			if !strings.Contains(got, want) {
+				t.Errorf("%v with %s: got %s, want %s", c.v, Kinds[i], got, want)
+			}
+		}
+	}
+}
+
+func TestChecked(t *testing.T) {
+	var a Int64
+	if _, err := a.Add(math.MaxInt64, 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("MaxInt64+1 gives %v", err)
+	}
+	if _, err := a.Sub(math.MinInt64, 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("MinInt64-1 gives %v", err)
+	}
+	if _, err := a.Mul(math.MinInt64, -1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("MinInt64*-1 gives %v", err)
+	}
+	if _, err := a.Quo(math.MinInt64, -1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("MinInt64/-1 gives %v", err)
+	}
+	if v, err := a.Quo(-7, 2); v != -3 || err != nil {
+		t.Errorf("-7/2 gives %d, %v", v, err)
+	}
+	var r BigRat
+	if v, err := r.Rem(r.Int(-7), r.Int(2)); err != nil || r.String(v) != "-1" {
+		t.Errorf("-7%%2 gives %v, %v", v, err)
+	}
+	if err := CheckKind("float"); err == nil {
+		t.Errorf("float is not a kind of number")
+	}
+}
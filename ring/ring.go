@@ -0,0 +1,258 @@
+// Package ring is a circular sequence of values, for puzzles that move
+// things around in a circle, e.g., mixing the numbers in Day 20, or taking
+// turns with the directions in Day 23. Each value is held in an element,
+// which stays the same however it moves, so it is a handle on that value
+// even if the same value appears many times.
+//
+//	r := ring.New(1, 2, -3, 3, -2, 0, 4)
+//	e := r.At(2)                  // the element holding -3
+//	r.Move(e, e.Value)            // three places back, going round
+//	r.Index(e)                    // where it is now
+//	r.Offset(e, 1000).Value       // the value 1000 places after it
+//
+// The ring has a start, so that elements have an index (their place after
+// the start), but going past the end wraps round to the start, and any
+// index is taken modulo the length. It is kept as an implicit treap: a
+// binary tree in ring order, balanced by random priorities, in which each
+// element knows the size of its subtree and its parent, so that every
+// operation takes logarithmic time.
+//
+// AK, Dec 2022
+
+package ring
+
+import "math/rand"
+
+// An element of a ring, holding a value
+type Elem[T any] struct {
+	Value               T
+	ring                *Ring[T] // the ring it is in, nil if removed
+	prio                uint32   // random priority, higher nearer the root
+	size                int      // number of elements in this subtree
+	left, right, parent *Elem[T]
+}
+
+// A ring of values
+type Ring[T any] struct {
+	root *Elem[T]
+	rng  *rand.Rand
+}
+
+// Make a ring with the given values, in order (the same priorities every
+// time, so that runs are repeatable)
+func New[T any](values ...T) *Ring[T] {
+	r := &Ring[T]{rng: rand.New(rand.NewSource(1))}
+	for _, v := range values {
+		r.root = merge(r.root, r.elem(v))
+	}
+	return r
+}
+
+// A new element for a value, not yet in the tree
+func (r *Ring[T]) elem(v T) *Elem[T] {
+	return &Elem[T]{Value: v, ring: r, prio: r.rng.Uint32(), size: 1}
+}
+
+// Number of elements in the ring
+func (r *Ring[T]) Len() int {
+	return size(r.root)
+}
+
+// The element at an index (modulo the length), nil if the ring is empty
+func (r *Ring[T]) At(i int) *Elem[T] {
+	n := r.Len()
+	if n == 0 {
+		return nil
+	}
+	i = mod(i, n)
+	e := r.root
+	for {
+		l := size(e.left)
+		switch {
+		case i < l:
+			e = e.left
+		case i == l:
+			return e
+		default:
+			i -= l + 1
+			e = e.right
+		}
+	}
+}
+
+// Index of an element, -1 if it isn't in the ring
+func (r *Ring[T]) Index(e *Elem[T]) int {
+	if e == nil || e.ring != r {
+		return -1
+	}
+	i := size(e.left)
+	for ; e.parent != nil; e = e.parent {
+		if e == e.parent.right {
+			i += size(e.parent.left) + 1
+		}
+	}
+	return i
+}
+
+// The element k places after e (before, if negative), going round the
+// ring, nil if e isn't in the ring
+func (r *Ring[T]) Offset(e *Elem[T], k int) *Elem[T] {
+	i := r.Index(e)
+	if i < 0 {
+		return nil
+	}
+	return r.At(i + k)
+}
+
+// How many places after from an element is, going round the ring (0 to
+// Len-1), -1 if either isn't in the ring
+func (r *Ring[T]) IndexFrom(from, e *Elem[T]) int {
+	i, j := r.Index(from), r.Index(e)
+	if i < 0 || j < 0 {
+		return -1
+	}
+	return mod(j-i, r.Len())
+}
+
+// Insert a value so that it ends up at an index (modulo the new length,
+// so that -1 puts it at the end), and return its element
+func (r *Ring[T]) Insert(i int, v T) *Elem[T] {
+	e := r.elem(v)
+	a, b := split(r.root, mod(i, r.Len()+1))
+	r.root = merge(merge(a, e), b)
+	return e
+}
+
+// Remove an element from the ring, and return its value (does nothing if
+// it isn't in the ring)
+func (r *Ring[T]) Remove(e *Elem[T]) T {
+	i := r.Index(e)
+	if i < 0 {
+		return e.Value
+	}
+	r.cut(i)
+	e.ring = nil
+	return e.Value
+}
+
+// Take the element at an index out of the tree, leaving it on its own
+func (r *Ring[T]) cut(i int) {
+	a, b := split(r.root, i)
+	_, c := split(b, 1)
+	r.root = merge(a, c)
+}
+
+// Move an element d places forward (backward, if negative) among the
+// others, going round the ring: as there are Len-1 others, moving Len-1
+// places brings it back to where it was. Does nothing if the element isn't
+// in the ring.
+func (r *Ring[T]) Move(e *Elem[T], d int) {
+	i := r.Index(e)
+	n := r.Len()
+	if i < 0 || n < 2 {
+		return
+	}
+	r.cut(i)
+	a, b := split(r.root, mod(i+d%(n-1), n-1))
+	r.root = merge(merge(a, e), b)
+}
+
+// Move the start of the ring k places forward (backward, if negative), so
+// that the element at index k is first
+func (r *Ring[T]) Rotate(k int) {
+	if n := r.Len(); n > 0 {
+		a, b := split(r.root, mod(k, n))
+		r.root = merge(b, a)
+	}
+}
+
+// The elements in order from the start
+func (r *Ring[T]) Elems() []*Elem[T] {
+	elems := make([]*Elem[T], 0, r.Len())
+	var walk func(e *Elem[T])
+	walk = func(e *Elem[T]) {
+		if e != nil {
+			walk(e.left)
+			elems = append(elems, e)
+			walk(e.right)
+		}
+	}
+	walk(r.root)
+	return elems
+}
+
+// The values in order from the start
+func (r *Ring[T]) Values() []T {
+	values := make([]T, 0, r.Len())
+	for _, e := range r.Elems() {
+		values = append(values, e.Value)
+	}
+	return values
+}
+
+// Number of elements in a subtree, 0 if none
+func size[T any](e *Elem[T]) int {
+	if e == nil {
+		return 0
+	}
+	return e.size
+}
+
+// Fix the size of an element, and the parent pointers of its children,
+// after they change
+func (e *Elem[T]) update() {
+	e.size = 1 + size(e.left) + size(e.right)
+	if e.left != nil {
+		e.left.parent = e
+	}
+	if e.right != nil {
+		e.right.parent = e
+	}
+}
+
+// Join two trees, all of a before all of b
+func merge[T any](a, b *Elem[T]) *Elem[T] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.prio > b.prio:
+		a.right = merge(a.right, b)
+		a.update()
+		return a
+	default:
+		b.left = merge(a, b.left)
+		b.update()
+		return b
+	}
+}
+
+// Split a tree into the first k elements and the rest
+func split[T any](e *Elem[T], k int) (*Elem[T], *Elem[T]) {
+	if e == nil {
+		return nil, nil
+	}
+	var a, b *Elem[T]
+	if size(e.left) >= k {
+		a, e.left = split(e.left, k)
+		e.update()
+		b = e
+	} else {
+		e.right, b = split(e.right, k-size(e.left)-1)
+		e.update()
+		a = e
+	}
+	if a != nil {
+		a.parent = nil
+	}
+	if b != nil {
+		b.parent = nil
+	}
+	return a, b
+}
+
+// Remainder of a divided by n, from 0 to n-1 even if a is negative
+func mod(a, n int) int {
+	return (a%n + n) % n
+}
@@ -0,0 +1,140 @@
+// Unit tests for the ring package, and a fuzz test against a naive model
+// of a ring in a slice, e.g.,
+//
+//	go test ./ring -fuzz FuzzRing -fuzztime 30s
+
+package ring
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+
+	// Mixing the numbers in the Day 20 sample once, starting from 0
+	r := New(1, 2, -3, 3, -2, 0, 4)
+	for _, e := range r.Elems() {
+		r.Move(e, e.Value)
+	}
+	var zero *Elem[int]
+	for _, e := range r.Elems() {
+		if e.Value == 0 {
+			zero = e
+		}
+	}
+	r.Rotate(r.Index(zero))
+	if got, want := fmt.Sprint(r.Values()), "[0 3 -2 1 2 -3 4]"; got != want {
+		t.Errorf("mixed to %s, want %s", got, want)
+	}
+	if got := r.Offset(zero, 1000).Value + r.Offset(zero, 2000).Value + r.Offset(zero, 3000).Value; got != 3 {
+		t.Errorf("sum after zero %d, want 3", got)
+	}
+
+	// Rotating directions, as in Day 23
+	d := New('N', 'S', 'W', 'E')
+	d.Rotate(1)
+	if got := string(d.Values()); got != "SWEN" {
+		t.Errorf("rotated to %s, want SWEN", got)
+	}
+
+	// Elements not in the ring, and an empty ring
+	e := d.At(0)
+	if d.Remove(e) != 'S' || d.Index(e) != -1 || d.Offset(e, 1) != nil || d.Len() != 3 {
+		t.Errorf("removed element still in ring")
+	}
+	d.Move(e, 1)
+	if d.Len() != 3 || New[int]().At(0) != nil {
+		t.Errorf("moved element not in ring, or found one in empty ring")
+	}
+}
+
+// A naive ring: the elements in order, in a slice
+type model []*Elem[int]
+
+func (m model) index(e *Elem[int]) int {
+	for i, x := range m {
+		if x == e {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m model) remove(i int) model {
+	return append(m[:i:i], m[i+1:]...)
+}
+
+func (m model) insert(i int, e *Elem[int]) model {
+	return append(m[:i:i], append(model{e}, m[i:]...)...)
+}
+
+// Do random operations, from the bytes, on a ring and on the model, and
+// check they agree after each one
+func FuzzRing(f *testing.F) {
+	f.Add([]byte{0, 0, 0, 1, 0, 2, 2, 0, 5, 1, 3, 1, 250})
+	f.Add([]byte{0, 3, 0, 7, 0, 1, 2, 1, 200, 4, 3, 1, 0, 5, 2, 2, 255})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		r := New[int]()
+		var m model
+		next := 0
+		for k := 0; k+2 < len(ops); k += 3 {
+			op, a, b := ops[k]%5, int(ops[k+1]), int(int8(ops[k+2]))
+			switch op {
+			case 0: // insert at an index, modulo the new length
+				e := r.Insert(a-128, next)
+				m = m.insert(mod(a-128, len(m)+1), e)
+				next++
+			case 1: // remove an element
+				if len(m) > 0 {
+					e := m[a%len(m)]
+					r.Remove(e)
+					m = m.remove(a % len(m))
+				}
+			case 2: // move an element among the others
+				if len(m) > 1 {
+					i := a % len(m)
+					e := m[i]
+					r.Move(e, b*1000+a)
+					m = m.remove(i)
+					m = m.insert(mod(i+b*1000+a, len(m)), e)
+				}
+			case 3: // rotate the start
+				if len(m) > 0 {
+					k := mod(b, len(m))
+					m = append(m[k:], m[:k]...)
+				}
+				r.Rotate(b)
+			case 4: // offsets from an element
+				if len(m) > 0 {
+					e := m[a%len(m)]
+					if want := m[mod(m.index(e)+b, len(m))]; r.Offset(e, b) != want {
+						t.Fatalf("offset %d from %d: got %d, want %d", b, e.Value, r.Offset(e, b).Value, want.Value)
+					}
+					if got, want := r.IndexFrom(e, m[0]), mod(-(a%len(m)), len(m)); got != want {
+						t.Fatalf("index of first from %d: got %d, want %d", e.Value, got, want)
+					}
+				}
+			}
+
+			// Same elements in the same order, each knowing where it is
+			if r.Len() != len(m) {
+				t.Fatalf("op %d: length %d, want %d", k/3, r.Len(), len(m))
+			}
+			for i, e := range r.Elems() {
+				if e != m[i] || r.Index(e) != i || r.At(i) != e {
+					t.Fatalf("op %d: %v, want %v", k/3, r.Values(), values(m))
+				}
+			}
+		}
+	})
+}
+
+// The values in the model
+func values(m model) []int {
+	var v []int
+	for _, e := range m {
+		v = append(v, e.Value)
+	}
+	return v
+}
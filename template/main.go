@@ -1,22 +1,44 @@
+//go:build ignore
+
 // Advent of Code 2022, Day 2X
 //
 // Description:
 //
 // AK, 2X Dec 2022
 
-package main
+package template
 
 import (
-	"fmt"
+	//"fmt"
+	"io"
 	//"strings"
+
+	"adventofcode2022/aoc"
+	//"adventofcode2022/aocutil"
 )
 
-func main() {
+func init() {
+	aoc.Register(0, func() aoc.Solver { return &solver{} })
+}
+
+// The parsed input
+type solver struct {
+	lines []string
+}
+
+// Read the input file
+func (s *solver) Parse(r io.Reader) error {
+	var err error
+	s.lines, err = aoc.Lines(r)
+	return err
+}
+
+// Part 1
+func (s *solver) Part1() (string, error) {
+	return "", aoc.ErrNoSolution
+}
 
-	// Read the input file
-	fname := "sample.txt"
-	//fname = "input.txt"
-	for _, l := range readLines(fname) {
-		fmt.Println(l)
-	}
+// Part 2
+func (s *solver) Part2() (string, error) {
+	return "", aoc.ErrNoSolution
 }
@@ -1,6 +1,8 @@
+//go:build ignore
+
 // Unit tests for this Advent of Code submission
 
-package main
+package template
 
 import (
 	"testing"